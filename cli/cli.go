@@ -2,10 +2,13 @@ package cli
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"sort"
 	"strings"
 	"time"
 
+	"lazytime/config"
 	"lazytime/storage"
 )
 
@@ -42,43 +45,100 @@ func ClampDuration(entry storage.Entry, start, end, now time.Time) time.Duration
 }
 
 // Summarize aggregates entries by tag within a time range.
-// Returns total duration and a map of tag -> duration.
-func Summarize(entries []storage.Entry, start, end, now time.Time) (time.Duration, map[string]time.Duration) {
-	tagTotals := make(map[string]time.Duration)
-	var total time.Duration
+// Hierarchical tags roll up into their parents: an entry tagged #a/b counts
+// toward both "a" and "a/b", and toward "a" only once even with several children.
+// Returns the rounded total, the unrounded total and a map of tag -> rounded duration.
+func Summarize(entries []storage.Entry, start, end, now time.Time, rounding storage.Rounding) (time.Duration, time.Duration, map[string]time.Duration) {
+	return SummarizeBy(entries, start, end, now, rounding, func(entry storage.Entry) []string {
+		tags := entry.TagPaths()
+		if len(tags) == 0 {
+			return []string{"(untagged)"}
+		}
+		return tags
+	})
+}
+
+// SummarizeBy aggregates entries within a time range under the group keys
+// returned by keys for each entry. Each entry counts once toward every key.
+// Returns the rounded total, the unrounded total and a map of key -> rounded duration.
+func SummarizeBy(entries []storage.Entry, start, end, now time.Time, rounding storage.Rounding, keys func(storage.Entry) []string) (time.Duration, time.Duration, map[string]time.Duration) {
+	groupTotals := make(map[string]time.Duration)
+	var total, rawTotal time.Duration
 
 	for _, entry := range entries {
 		chunk := ClampDuration(entry, start, end, now)
 		if chunk <= 0 {
 			continue
 		}
+		rawTotal += chunk
+		chunk = rounding.Entry(chunk)
 		total += chunk
 
-		tags := entry.Tags()
-		if len(tags) == 0 {
-			tags = []string{"(untagged)"}
+		for _, key := range keys(entry) {
+			groupTotals[key] += chunk
 		}
+	}
 
-		for _, tag := range tags {
-			tagTotals[tag] += chunk
-		}
+	total = rounding.Aggregate(total)
+	for key, duration := range groupTotals {
+		groupTotals[key] = rounding.Aggregate(duration)
 	}
 
-	return total, tagTotals
+	return total, rawTotal, groupTotals
 }
 
-// CommandStart starts a new active entry.
-func CommandStart(text string, atTime string) error {
-	entries, err := storage.ReadEntries("")
-	if err != nil {
-		return fmt.Errorf("failed to read entries: %w", err)
+// attributeKeys returns a grouping function that groups entries by the
+// values of the given attribute key. Entries without it go under "(no KEY)".
+// Values are matched case-insensitively, as in attribute filters, and keep
+// the spelling they were first seen with.
+func attributeKeys(key string) func(storage.Entry) []string {
+	key = strings.ToLower(key)
+	names := make(map[string]string)
+	return func(entry storage.Entry) []string {
+		var values []string
+		seen := make(map[string]bool)
+		for _, attr := range entry.Attributes() {
+			folded := strings.ToLower(attr.Value)
+			if attr.Key != key || seen[folded] {
+				continue
+			}
+			seen[folded] = true
+			if _, ok := names[folded]; !ok {
+				names[folded] = attr.Value
+			}
+			values = append(values, names[folded])
+		}
+		if len(values) == 0 {
+			return []string{"(no " + key + ")"}
+		}
+		return values
 	}
+}
 
-	openIdx := storage.FindOpen(entries)
-	if openIdx != -1 {
-		return fmt.Errorf("there is already an active entry. Stop it before starting another")
+// FilterByAttributes returns the entries matching every attribute filter.
+// A filter with an empty value matches any entry that has the key.
+func FilterByAttributes(entries []storage.Entry, filters []storage.Attribute) []storage.Entry {
+	if len(filters) == 0 {
+		return entries
 	}
+	var filtered []storage.Entry
+	for _, entry := range entries {
+		matches := true
+		for _, filter := range filters {
+			if !entry.MatchesAttribute(filter.Key, filter.Value) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
 
+// CommandStart starts a new active entry.
+func CommandStart(out io.Writer, text string, atTime string) error {
 	now := storage.LocalNow()
 	when, err := storage.ParseWhen(atTime, now)
 	if err != nil {
@@ -92,63 +152,61 @@ func CommandStart(text string, atTime string) error {
 		Text:  text,
 	}
 
-	if err := storage.AppendEntry(newEntry, ""); err != nil {
-		return fmt.Errorf("failed to append entry: %w", err)
+	err = storage.UpdateEntries("", func(entries []storage.Entry) ([]storage.Entry, error) {
+		if storage.FindOpen(entries) != -1 {
+			return nil, fmt.Errorf("there is already an active entry. Stop it before starting another")
+		}
+		return append(entries, newEntry), nil
+	})
+	if err != nil {
+		return err
 	}
 
 	localWhen := whenUTC.In(now.Location())
-	fmt.Printf("Started: %s @ %s\n", text, localWhen.Format("2006-01-02 15:04"))
+	fmt.Fprintf(out, "Started: %s @ %s\n", text, localWhen.Format("2006-01-02 15:04"))
 	return nil
 }
 
 // CommandStop stops the active entry.
-func CommandStop(atTime string) error {
-	entries, err := storage.ReadEntries("")
-	if err != nil {
-		return fmt.Errorf("failed to read entries: %w", err)
-	}
-
-	openIdx := storage.FindOpen(entries)
-	if openIdx == -1 {
-		return fmt.Errorf("no active entry to stop")
-	}
-
+func CommandStop(out io.Writer, atTime string) error {
 	now := storage.LocalNow()
 	when, err := storage.ParseWhen(atTime, now)
 	if err != nil {
 		return err
 	}
-
 	whenUTC := storage.ToUTC(when)
-	openEntry := entries[openIdx]
 
-	if whenUTC.Before(openEntry.Start) || whenUTC.Equal(openEntry.Start) {
-		return fmt.Errorf("stop time must be after the start time")
-	}
+	var updated storage.Entry
+	err = storage.UpdateEntries("", func(entries []storage.Entry) ([]storage.Entry, error) {
+		openIdx := storage.FindOpen(entries)
+		if openIdx == -1 {
+			return nil, fmt.Errorf("no active entry to stop")
+		}
 
-	updated := storage.Entry{
-		Start: openEntry.Start,
-		End:   &whenUTC,
-		Text:  openEntry.Text,
-	}
+		openEntry := entries[openIdx]
+		if whenUTC.Before(openEntry.Start) || whenUTC.Equal(openEntry.Start) {
+			return nil, fmt.Errorf("stop time must be after the start time")
+		}
 
-	entries[openIdx] = updated
-	if err := storage.WriteEntries(entries, ""); err != nil {
-		return fmt.Errorf("failed to write entries: %w", err)
+		updated = storage.Entry{
+			Start: openEntry.Start,
+			End:   &whenUTC,
+			Text:  openEntry.Text,
+		}
+		entries[openIdx] = updated
+		return entries, nil
+	})
+	if err != nil {
+		return err
 	}
 
 	elapsed := updated.Duration(whenUTC)
-	fmt.Printf("Stopped '%s' after %s.\n", updated.Text, FormatDuration(elapsed))
+	fmt.Fprintf(out, "Stopped '%s' after %s.\n", updated.Text, FormatDuration(elapsed))
 	return nil
 }
 
 // CommandAdd adds a completed entry retroactively.
-func CommandAdd(start, end, text string) error {
-	entries, err := storage.ReadEntries("")
-	if err != nil {
-		return fmt.Errorf("failed to read entries: %w", err)
-	}
-
+func CommandAdd(out io.Writer, start, end, text string) error {
 	now := storage.LocalNow()
 	startTime, err := storage.ParseWhen(start, now)
 	if err != nil {
@@ -172,23 +230,25 @@ func CommandAdd(start, end, text string) error {
 		Text:  text,
 	}
 
-	overlapEntry, overlapDuration, hasOverlap := storage.CheckOverlap(entries, newEntry, endUTC)
-	if hasOverlap {
-		otherLocal := overlapEntry.Start.In(now.Location())
-		return fmt.Errorf(
-			"new entry overlaps with existing entry starting at %s for %s",
-			otherLocal.Format("2006-01-02 15:04"),
-			FormatDuration(overlapDuration),
-		)
-	}
-
-	if err := storage.AppendEntry(newEntry, ""); err != nil {
-		return fmt.Errorf("failed to append entry: %w", err)
+	err = storage.UpdateEntries("", func(entries []storage.Entry) ([]storage.Entry, error) {
+		overlapEntry, overlapDuration, hasOverlap := storage.CheckOverlap(entries, newEntry, endUTC)
+		if hasOverlap {
+			otherLocal := overlapEntry.Start.In(now.Location())
+			return nil, fmt.Errorf(
+				"new entry overlaps with existing entry starting at %s for %s",
+				otherLocal.Format("2006-01-02 15:04"),
+				FormatDuration(overlapDuration),
+			)
+		}
+		return append(entries, newEntry), nil
+	})
+	if err != nil {
+		return err
 	}
 
 	startLocal := startUTC.In(now.Location())
 	endLocal := endUTC.In(now.Location())
-	fmt.Printf(
+	fmt.Fprintf(out,
 		"Added %s entry %s -> %s : %s\n",
 		FormatDuration(newEntry.Duration(endUTC)),
 		startLocal.Format("2006-01-02 15:04"),
@@ -199,7 +259,7 @@ func CommandAdd(start, end, text string) error {
 }
 
 // CommandStatus shows the current active entry.
-func CommandStatus() error {
+func CommandStatus(out io.Writer) error {
 	entries, err := storage.ReadEntries("")
 	if err != nil {
 		return fmt.Errorf("failed to read entries: %w", err)
@@ -207,7 +267,7 @@ func CommandStatus() error {
 
 	openIdx := storage.FindOpen(entries)
 	if openIdx == -1 {
-		fmt.Println("No active entry.")
+		fmt.Fprintln(out, "No active entry.")
 		return nil
 	}
 
@@ -216,7 +276,7 @@ func CommandStatus() error {
 	elapsed := entry.Duration(now)
 	localStart := entry.Start.In(time.Local)
 
-	fmt.Printf(
+	fmt.Fprintf(out,
 		"Active: %s (since %s, %s elapsed)\n",
 		entry.Text,
 		localStart.Format("15:04"),
@@ -225,25 +285,32 @@ func CommandStatus() error {
 	return nil
 }
 
-// CommandReport generates a report of logged time by tag for a date range.
-func CommandReport(fromDate, toDate string, week, lastWeek bool) error {
-	entries, err := storage.ReadEntries("")
-	if err != nil {
-		return fmt.Errorf("failed to read entries: %w", err)
-	}
+// ReportOptions holds the flags accepted by the report command.
+type ReportOptions struct {
+	FromDate   string
+	ToDate     string
+	Week       bool
+	LastWeek   bool
+	Round      string   // rounding spec overriding the config, e.g. "up:15m"
+	RoundScope string   // "entry" or "aggregate", overriding the config
+	Attrs      []string // attribute filters: "key", "key:value" or "key=value"
+	GroupBy    string   // "tag" (default) or "attr:KEY"
+}
 
-	now := storage.LocalNow()
+// resolveRange resolves the --from/--to/--week/--last-week flags of command
+// into a local date range from the start of the first day to the end of the
+// last. Without --to the range ends where it starts.
+func resolveRange(command, fromDate, toDate string, week, lastWeek bool, now time.Time) (from, to time.Time, err error) {
 	tz := now.Location()
 	today := now
 
 	if week && lastWeek {
-		return fmt.Errorf("choose only one of --week or --last-week")
+		return time.Time{}, time.Time{}, fmt.Errorf("choose only one of --week or --last-week")
 	}
 	if (week || lastWeek) && (fromDate != "" || toDate != "") {
-		return fmt.Errorf("cannot combine --week/--last-week with --from/--to")
+		return time.Time{}, time.Time{}, fmt.Errorf("cannot combine --week/--last-week with --from/--to")
 	}
 
-	var from, to time.Time
 	if week {
 		weekday := int(today.Weekday())
 		if weekday == 0 {
@@ -271,7 +338,7 @@ func CommandReport(fromDate, toDate string, week, lastWeek bool) error {
 		} else {
 			parsed, err := storage.ParseDate(fromDate)
 			if err != nil {
-				return fmt.Errorf("invalid from date: %w", err)
+				return time.Time{}, time.Time{}, fmt.Errorf("invalid from date: %w", err)
 			}
 			from = time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 0, 0, 0, 0, tz)
 		}
@@ -281,32 +348,98 @@ func CommandReport(fromDate, toDate string, week, lastWeek bool) error {
 		} else {
 			parsed, err := storage.ParseDate(toDate)
 			if err != nil {
-				return fmt.Errorf("invalid to date: %w", err)
+				return time.Time{}, time.Time{}, fmt.Errorf("invalid to date: %w", err)
 			}
 			to = time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 23, 59, 59, 0, tz)
 		}
 	}
 
 	if to.Before(from) {
-		return fmt.Errorf("report end date cannot be before start date")
+		return time.Time{}, time.Time{}, fmt.Errorf("%s end date cannot be before start date", command)
+	}
+	return from, to, nil
+}
+
+// roundingPolicy resolves the rounding policy from config and report flags.
+func roundingPolicy(cfg config.Config, opts ReportOptions) (storage.Rounding, error) {
+	if opts.Round != "" {
+		cfg.Rounding = opts.Round
+	}
+	if opts.RoundScope != "" {
+		cfg.RoundingScope = opts.RoundScope
+	}
+	return cfg.RoundingPolicy()
+}
+
+// CommandReport generates a report of logged time by tag for a date range.
+func CommandReport(out io.Writer, opts ReportOptions) error {
+	entries, err := storage.ReadEntries("")
+	if err != nil {
+		return fmt.Errorf("failed to read entries: %w", err)
+	}
+
+	cfg, err := config.Load("")
+	if err != nil {
+		return err
+	}
+	entries = storage.ApplyTagAliases(entries, cfg.TagAliases)
+
+	rounding, err := roundingPolicy(cfg, opts)
+	if err != nil {
+		return err
+	}
+
+	var filters []storage.Attribute
+	for _, attr := range opts.Attrs {
+		filter := storage.ParseAttributeFilter(attr)
+		if filter.Key == "" {
+			return fmt.Errorf("invalid attribute filter: %s", attr)
+		}
+		filters = append(filters, filter)
+	}
+	entries = FilterByAttributes(entries, filters)
+
+	groupAttr := ""
+	switch {
+	case opts.GroupBy == "" || opts.GroupBy == "tag":
+	case strings.HasPrefix(opts.GroupBy, "attr:") && len(opts.GroupBy) > len("attr:"):
+		groupAttr = strings.TrimPrefix(opts.GroupBy, "attr:")
+	default:
+		return fmt.Errorf("invalid --group-by value: %s (use tag or attr:KEY)", opts.GroupBy)
+	}
+
+	from, to, err := resolveRange("report", opts.FromDate, opts.ToDate, opts.Week, opts.LastWeek, storage.LocalNow())
+	if err != nil {
+		return err
 	}
 
 	startUTC := from.UTC()
 	endUTC := to.UTC()
 	nowUTC := storage.UTCNow()
 
-	total, tagTotals := Summarize(entries, startUTC, endUTC, nowUTC)
+	var total, rawTotal time.Duration
+	var tagTotals map[string]time.Duration
+	if groupAttr != "" {
+		total, rawTotal, tagTotals = SummarizeBy(entries, startUTC, endUTC, nowUTC, rounding, attributeKeys(groupAttr))
+	} else {
+		total, rawTotal, tagTotals = Summarize(entries, startUTC, endUTC, nowUTC, rounding)
+	}
 
-	if total == 0 {
-		fmt.Println("No entries in the selected range.")
+	if rawTotal == 0 {
+		fmt.Fprintln(out, "No entries in the selected range.")
 		return nil
 	}
 
 	fromDateStr := from.Format("2006-01-02")
 	toDateStr := to.Format("2006-01-02")
-	fmt.Printf("Report %s to %s\n", fromDateStr, toDateStr)
+	if groupAttr != "" {
+		fmt.Fprintf(out, "Report %s to %s by %s\n", fromDateStr, toDateStr, strings.ToLower(groupAttr))
+	} else {
+		fmt.Fprintf(out, "Report %s to %s\n", fromDateStr, toDateStr)
+	}
 
-	// Sort tags case-insensitively but preserve original spelling
+	// Sort tags case-insensitively but preserve original spelling.
+	// Child tags follow their parent and are indented one level per depth.
 	type tagItem struct {
 		tag      string
 		duration time.Duration
@@ -316,17 +449,38 @@ func CommandReport(fromDate, toDate string, week, lastWeek bool) error {
 		sortedTags = append(sortedTags, tagItem{tag: tag, duration: duration})
 	}
 	sort.Slice(sortedTags, func(i, j int) bool {
-		return strings.ToLower(sortedTags[i].tag) < strings.ToLower(sortedTags[j].tag)
+		if groupAttr != "" {
+			return strings.ToLower(sortedTags[i].tag) < strings.ToLower(sortedTags[j].tag)
+		}
+		return storage.CompareTags(sortedTags[i].tag, sortedTags[j].tag) < 0
 	})
 
 	for _, item := range sortedTags {
-		fmt.Printf("- %s: %s\n", item.tag, FormatDuration(item.duration))
+		if groupAttr != "" {
+			fmt.Fprintf(out, "- %s: %s\n", item.tag, FormatDuration(item.duration))
+			continue
+		}
+		indent := strings.Repeat("  ", storage.TagDepth(item.tag))
+		fmt.Fprintf(out, "%s- %s: %s\n", indent, storage.TagName(item.tag), FormatDuration(item.duration))
+	}
+	if rounding.Enabled() {
+		fmt.Fprintf(out, "Total: %s (unrounded %s, rounding %s per %s)\n",
+			FormatDuration(total), FormatDuration(rawTotal), rounding, roundScopeName(rounding.Scope))
+	} else {
+		fmt.Fprintf(out, "Total: %s\n", FormatDuration(total))
 	}
-	fmt.Printf("Total: %s\n", FormatDuration(total))
 
 	return nil
 }
 
+// roundScopeName returns the user-facing name of a rounding scope.
+func roundScopeName(scope storage.RoundScope) string {
+	if scope == storage.RoundPerAggregate {
+		return "aggregate"
+	}
+	return "entry"
+}
+
 // CommandTUI launches the terminal UI.
 func CommandTUI() error {
 	// Import tui package and call LaunchTUI
@@ -343,25 +497,92 @@ func RunCLI(args []string) error {
 	command := args[0]
 	remaining := args[1:]
 
+	if changesLog(command, remaining) {
+		return runStoreCommand(command, remaining)
+	}
+
+	switch command {
+	case "status", "report", "tags":
+		return execStoreCommand(command, remaining, os.Stdout)
+
+	case "daemon":
+		return CommandDaemon()
+
+	case "gaps":
+		var opts GapsOptions
+		for i := 0; i < len(remaining); i++ {
+			if remaining[i] == "--from" {
+				if i+1 >= len(remaining) {
+					return fmt.Errorf("--from requires a date value")
+				}
+				opts.FromDate = remaining[i+1]
+				i++
+			} else if remaining[i] == "--to" {
+				if i+1 >= len(remaining) {
+					return fmt.Errorf("--to requires a date value")
+				}
+				opts.ToDate = remaining[i+1]
+				i++
+			} else if remaining[i] == "--week" {
+				opts.Week = true
+			} else if remaining[i] == "--last-week" {
+				opts.LastWeek = true
+			} else if remaining[i] == "--min" {
+				if i+1 >= len(remaining) {
+					return fmt.Errorf("--min requires a duration (e.g. 15m)")
+				}
+				opts.Min = remaining[i+1]
+				i++
+			}
+		}
+		return CommandGaps(os.Stdout, opts)
+
+	case "remind":
+		var opts RemindOptions
+		for i := 0; i < len(remaining); i++ {
+			if remaining[i] == "--interval" {
+				if i+1 >= len(remaining) {
+					return fmt.Errorf("--interval requires a duration (e.g. 30s)")
+				}
+				opts.Interval = remaining[i+1]
+				i++
+			} else if remaining[i] == "--test" {
+				opts.Test = true
+			}
+		}
+		return CommandRemind(opts)
+
+	case "tui":
+		return CommandTUI()
+
+	default:
+		return fmt.Errorf("unknown command: %s", command)
+	}
+}
+
+// execStoreCommand parses the arguments of a store command and runs it on
+// the log file, printing to out.
+func execStoreCommand(command string, remaining []string, out io.Writer) error {
 	switch command {
 	case "start":
 		if len(remaining) == 0 {
 			return fmt.Errorf("start command requires text argument")
 		}
 		text := remaining[0]
+		remaining = remaining[1:]
 		var atTime string
-		if len(remaining) > 1 && remaining[1] == "--at" {
-			if len(remaining) < 3 {
+		if len(remaining) > 0 && remaining[0] == "--at" {
+			if len(remaining) < 2 {
 				return fmt.Errorf("--at requires a time value")
 			}
-			atTime = remaining[2]
-			remaining = remaining[3:]
+			atTime = remaining[1]
+			remaining = remaining[2:]
 		}
 		// Handle text that might have been split
 		if len(remaining) > 0 {
 			text = strings.Join(append([]string{text}, remaining...), " ")
 		}
-		return CommandStart(text, atTime)
+		return CommandStart(out, text, atTime)
 
 	case "stop":
 		var atTime string
@@ -371,7 +592,7 @@ func RunCLI(args []string) error {
 			}
 			atTime = remaining[1]
 		}
-		return CommandStop(atTime)
+		return CommandStop(out, atTime)
 
 	case "add":
 		var start, end, text string
@@ -402,41 +623,62 @@ func RunCLI(args []string) error {
 		if text == "" {
 			return fmt.Errorf("add command requires text argument")
 		}
-		return CommandAdd(start, end, text)
+		return CommandAdd(out, start, end, text)
 
 	case "status":
-		return CommandStatus()
+		return CommandStatus(out)
 
 	case "report":
-		var fromDate, toDate string
-		week := false
-		lastWeek := false
+		var opts ReportOptions
 		for i := 0; i < len(remaining); i++ {
 			if remaining[i] == "--from" {
 				if i+1 >= len(remaining) {
 					return fmt.Errorf("--from requires a date value")
 				}
-				fromDate = remaining[i+1]
+				opts.FromDate = remaining[i+1]
 				i++
 			} else if remaining[i] == "--to" {
 				if i+1 >= len(remaining) {
 					return fmt.Errorf("--to requires a date value")
 				}
-				toDate = remaining[i+1]
+				opts.ToDate = remaining[i+1]
 				i++
 			} else if remaining[i] == "--week" {
-				week = true
+				opts.Week = true
 			} else if remaining[i] == "--last-week" {
-				lastWeek = true
+				opts.LastWeek = true
+			} else if remaining[i] == "--round" {
+				if i+1 >= len(remaining) {
+					return fmt.Errorf("--round requires a value (e.g. up:15m or none)")
+				}
+				opts.Round = remaining[i+1]
+				i++
+			} else if remaining[i] == "--round-scope" {
+				if i+1 >= len(remaining) {
+					return fmt.Errorf("--round-scope requires entry or aggregate")
+				}
+				opts.RoundScope = remaining[i+1]
+				i++
+			} else if remaining[i] == "--attr" {
+				if i+1 >= len(remaining) {
+					return fmt.Errorf("--attr requires a key or key:value filter")
+				}
+				opts.Attrs = append(opts.Attrs, remaining[i+1])
+				i++
+			} else if remaining[i] == "--group-by" {
+				if i+1 >= len(remaining) {
+					return fmt.Errorf("--group-by requires tag or attr:KEY")
+				}
+				opts.GroupBy = remaining[i+1]
+				i++
 			}
 		}
-		return CommandReport(fromDate, toDate, week, lastWeek)
+		return CommandReport(out, opts)
 
-	case "tui":
-		return CommandTUI()
+	case "tags":
+		return runTags(out, remaining)
 
 	default:
 		return fmt.Errorf("unknown command: %s", command)
 	}
 }
-
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"lazytime/daemon"
+	"lazytime/storage"
+)
+
+// storeCommands are the commands that change the log, run by the daemon
+// when it is running. Commands that only read the log run in the CLI's
+// process, with its config and time zone.
+var storeCommands = map[string]bool{
+	"start": true,
+	"stop":  true,
+	"add":   true,
+	"tags":  true, // rename and merge
+}
+
+// changesLog reports whether a command with its arguments writes the log;
+// tags only lists the tags unless renaming or merging them.
+func changesLog(command string, args []string) bool {
+	if command == "tags" {
+		return len(args) > 0 && (args[0] == "rename" || args[0] == "merge")
+	}
+	return storeCommands[command]
+}
+
+// runStoreCommand sends a store command to the daemon, or runs it on the
+// log file directly when no daemon is running.
+func runStoreCommand(command string, args []string) error {
+	req := daemon.Request{Command: command, Args: args, Log: storage.DefaultLogPath()}
+	resp, err := daemon.Call(daemon.SocketPath(), req)
+	if errors.Is(err, daemon.ErrNotRunning) {
+		return execStoreCommand(command, args, os.Stdout)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Print(resp.Output)
+	if !resp.OK {
+		return errors.New(resp.Error)
+	}
+	return nil
+}
+
+// CommandDaemon serves the store commands over the daemon socket until
+// interrupted.
+func CommandDaemon() error {
+	path := daemon.SocketPath()
+	listener, err := daemon.Listen(path)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	server := &daemon.Server{
+		LogPath:  storage.DefaultLogPath(),
+		Commands: storeCommands,
+		Handle:   execStoreCommand,
+		Logf: func(format string, args ...any) {
+			fmt.Printf("%s  %s\n", storage.LocalNow().Format("15:04:05"), fmt.Sprintf(format, args...))
+		},
+	}
+	fmt.Printf("Listening on %s (Ctrl+C to stop)\n", path)
+	return server.Serve(listener)
+}
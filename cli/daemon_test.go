@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"io"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"lazytime/config"
+	"lazytime/daemon"
+	"lazytime/storage"
+)
+
+// storeEnv points the log, config and daemon socket into a temporary
+// directory and returns the log and socket paths.
+func storeEnv(t *testing.T) (logPath, socketPath string) {
+	t.Helper()
+	dir := t.TempDir()
+	logPath = filepath.Join(dir, "log.txt")
+	socketPath = filepath.Join(dir, "daemon.sock")
+	t.Setenv(storage.LogEnvVar, logPath)
+	t.Setenv(config.ConfigEnvVar, filepath.Join(dir, "config.json"))
+	t.Setenv(daemon.SocketEnvVar, socketPath)
+	return logPath, socketPath
+}
+
+func TestStoreCommandWithoutDaemon(t *testing.T) {
+	logPath, _ := storeEnv(t)
+
+	if err := RunCLI([]string{"start", "Write docs #project"}); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	entries, err := storage.ReadEntries(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Text != "Write docs #project" || entries[0].End != nil {
+		t.Errorf("Expected a running entry written directly, got %v", entries)
+	}
+}
+
+func TestStoreCommandThroughDaemon(t *testing.T) {
+	logPath, socketPath := storeEnv(t)
+
+	var mu sync.Mutex
+	var handled []string
+	server := &daemon.Server{
+		LogPath:  logPath,
+		Commands: storeCommands,
+		Handle: func(command string, args []string, out io.Writer) error {
+			mu.Lock()
+			handled = append(handled, command)
+			mu.Unlock()
+			return execStoreCommand(command, args, out)
+		},
+	}
+	listener, err := daemon.Listen(socketPath)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go server.Serve(listener)
+	defer listener.Close()
+
+	if err := RunCLI([]string{"start", "Write docs #project"}); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if err := RunCLI([]string{"tags", "rename", "project", "docs"}); err != nil {
+		t.Fatalf("tags rename failed: %v", err)
+	}
+	if err := RunCLI([]string{"start", "Review"}); err == nil {
+		t.Error("Expected the daemon's error for a second running entry")
+	}
+
+	// Commands that only read the log run in this process
+	for _, args := range [][]string{{"status"}, {"report"}, {"tags"}} {
+		if err := RunCLI(args); err != nil {
+			t.Errorf("%s failed: %v", args[0], err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(handled) != 3 || handled[0] != "start" || handled[1] != "tags" || handled[2] != "start" {
+		t.Errorf("Expected the daemon to run only start, tags and start, got %v", handled)
+	}
+	entries, err := storage.ReadEntries(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Text != "Write docs #docs" {
+		t.Errorf("Expected the renamed running entry, got %v", entries)
+	}
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"lazytime/config"
+	"lazytime/storage"
+)
+
+// GapsOptions holds the flags of the gaps command.
+type GapsOptions struct {
+	FromDate string
+	ToDate   string
+	Week     bool
+	LastWeek bool
+	Min      string // minimum gap length, e.g. "15m" (default 5m)
+}
+
+// defaultMinGap is the shortest gap listed when --min is not given.
+const defaultMinGap = 5 * time.Minute
+
+// CommandGaps lists untracked intervals within the configured working hours.
+func CommandGaps(out io.Writer, opts GapsOptions) error {
+	entries, err := storage.ReadEntries("")
+	if err != nil {
+		return fmt.Errorf("failed to read entries: %w", err)
+	}
+
+	cfg, err := config.Load("")
+	if err != nil {
+		return err
+	}
+	hours, err := cfg.WorkingSchedule()
+	if err != nil {
+		return err
+	}
+
+	minGap := defaultMinGap
+	if opts.Min != "" {
+		minGap, err = time.ParseDuration(opts.Min)
+		if err != nil || minGap < 0 {
+			return fmt.Errorf("invalid --min duration: %s", opts.Min)
+		}
+	}
+
+	now := storage.LocalNow()
+	from, to, err := resolveRange("gaps", opts.FromDate, opts.ToDate, opts.Week, opts.LastWeek, now)
+	if err != nil {
+		return err
+	}
+	tz := now.Location()
+	if opts.ToDate == "" && !opts.Week && !opts.LastWeek {
+		// A single day covers the whole day
+		to = time.Date(from.Year(), from.Month(), from.Day(), 23, 59, 59, 0, tz)
+	}
+
+	gaps := storage.FindGaps(entries, from.UTC(), to.Add(time.Second).UTC(), storage.UTCNow(), hours, tz, minGap)
+	if len(gaps) == 0 {
+		fmt.Fprintln(out, "No gaps in the selected range.")
+		return nil
+	}
+
+	fmt.Fprintf(out, "Gaps %s to %s (working hours %s-%s)\n",
+		from.Format("2006-01-02"), to.Format("2006-01-02"),
+		formatClock(hours.Start), formatClock(hours.End))
+	var total time.Duration
+	for _, gap := range gaps {
+		startLocal := gap.Start.In(tz)
+		fmt.Fprintf(out, "  %s  %s - %s  %s\n",
+			startLocal.Format("2006-01-02"),
+			startLocal.Format("15:04"),
+			gap.End.In(tz).Format("15:04"),
+			FormatDuration(gap.Duration()))
+		total += gap.Duration()
+	}
+	fmt.Fprintf(out, "Untracked: %s in %d gaps\n", FormatDuration(total), len(gaps))
+	return nil
+}
+
+// formatClock formats an offset from midnight as HH:MM.
+func formatClock(offset time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(offset.Hours()), int(offset.Minutes())%60)
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"lazytime/config"
+	"lazytime/storage"
+)
+
+func TestResolveRange(t *testing.T) {
+	now := time.Date(2024, 1, 3, 14, 30, 0, 0, time.UTC)
+	dayStart := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	// Without --to the range ends where it starts
+	from, to, err := resolveRange("report", "2024-01-01", "", false, false, now)
+	if err != nil {
+		t.Fatalf("resolveRange failed: %v", err)
+	}
+	if !from.Equal(dayStart.AddDate(0, 0, -2)) || !to.Equal(from) {
+		t.Errorf("Expected the start of the from day, got %v to %v", from, to)
+	}
+
+	from, to, err = resolveRange("report", "2024-01-01", "2024-01-02", false, false, now)
+	if err != nil {
+		t.Fatalf("resolveRange failed: %v", err)
+	}
+	if !from.Equal(dayStart.AddDate(0, 0, -2)) || !to.Equal(dayStart.Add(-time.Second)) {
+		t.Errorf("Expected the two days, got %v to %v", from, to)
+	}
+
+	_, _, err = resolveRange("report", "2024-01-02", "2024-01-01", false, false, now)
+	if err == nil || err.Error() != "report end date cannot be before start date" {
+		t.Errorf("Expected the report range error, got %v", err)
+	}
+}
+
+func TestCommandGapsDefaultsToFromDay(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "log.txt")
+	t.Setenv(storage.LogEnvVar, logPath)
+	t.Setenv(config.ConfigEnvVar, filepath.Join(dir, "config.json"))
+
+	// Monday, working 09:00-10:00 local and nothing after
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.Local)
+	end := start.Add(time.Hour)
+	if err := storage.AppendEntry(storage.Entry{Start: start.UTC(), End: &end, Text: "Standup"}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := CommandGaps(&out, GapsOptions{FromDate: "2024-01-01"}); err != nil {
+		t.Fatalf("CommandGaps failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "10:00 - 17:00  7h00m") {
+		t.Errorf("Expected the afternoon gap, got:\n%s", out.String())
+	}
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"lazytime/config"
+	"lazytime/notify"
+	"lazytime/storage"
+)
+
+// RemindOptions holds the flags of the remind command.
+type RemindOptions struct {
+	Interval string // how often the rules are checked, e.g. "1m" (default)
+	Test     bool   // send a test notification and exit
+}
+
+// defaultRemindInterval is how often the rules are checked when
+// --interval is not given.
+const defaultRemindInterval = time.Minute
+
+// CommandRemind checks the configured notification rules until
+// interrupted, sending a desktop notification for each reminder due.
+func CommandRemind(opts RemindOptions) error {
+	cfg, err := config.Load("")
+	if err != nil {
+		return err
+	}
+	rules, err := reminderRules(cfg)
+	if err != nil {
+		return err
+	}
+	if !rules.Enabled() && !opts.Test {
+		return fmt.Errorf("no reminders configured; set notifications in %s", config.DefaultConfigPath())
+	}
+	notifier, err := notify.New(cfg.Notifications.Command)
+	if err != nil {
+		return err
+	}
+	if opts.Test {
+		return notifier.Notify("lazytime", "Notifications are working.")
+	}
+
+	interval := defaultRemindInterval
+	if opts.Interval != "" {
+		interval, err = time.ParseDuration(opts.Interval)
+		if err != nil || interval <= 0 {
+			return fmt.Errorf("invalid --interval duration: %s", opts.Interval)
+		}
+	}
+
+	fmt.Printf("Checking reminders every %s (Ctrl+C to stop)\n", interval)
+	reminder := notify.NewReminder(rules)
+	for {
+		if err := checkReminders(reminder, notifier, cfg.TagAliases); err != nil {
+			fmt.Printf("%s  error: %v\n", storage.LocalNow().Format("15:04"), err)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// reminderRules builds the reminder rules from the config, using the
+// working hours for the not tracking reminder.
+func reminderRules(cfg config.Config) (notify.Rules, error) {
+	var rules notify.Rules
+	var err error
+	if rules.NotTracking, rules.LongRunning, rules.DailyGoal, err = cfg.ReminderThresholds(); err != nil {
+		return notify.Rules{}, err
+	}
+	if rules.SummaryAt, rules.Summary, err = cfg.DailySummaryTime(); err != nil {
+		return notify.Rules{}, err
+	}
+	if rules.WorkingHours, err = cfg.WorkingSchedule(); err != nil {
+		return notify.Rules{}, err
+	}
+	return rules, nil
+}
+
+// checkReminders reads the log and sends the reminders due now. A
+// reminder that fails to send does not hold up the others and is retried
+// on the next check.
+func checkReminders(reminder *notify.Reminder, notifier notify.Notifier, aliases map[string]string) error {
+	entries, err := storage.ReadEntries("")
+	if err != nil {
+		return fmt.Errorf("failed to read entries: %w", err)
+	}
+	entries = storage.ApplyTagAliases(entries, aliases)
+
+	now := storage.LocalNow()
+	var errs []error
+	for _, notification := range reminder.Check(entries, now.UTC(), now.Location()) {
+		fmt.Printf("%s  %s: %s\n", now.Format("15:04"), notification.Title, notification.Body)
+		if err := notifier.Notify(notification.Title, notification.Body); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		reminder.Sent(notification)
+	}
+	return errors.Join(errs...)
+}
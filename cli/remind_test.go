@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"lazytime/notify"
+	"lazytime/storage"
+)
+
+// failingNotifier records the notifications sent and fails those whose
+// title is in fail.
+type failingNotifier struct {
+	fail map[string]bool
+	sent []string
+}
+
+func (n *failingNotifier) Notify(title, body string) error {
+	if n.fail[title] {
+		return errors.New("notifier unavailable")
+	}
+	n.sent = append(n.sent, title)
+	return nil
+}
+
+func TestCheckRemindersRetriesFailedNotifications(t *testing.T) {
+	t.Setenv(storage.LogEnvVar, filepath.Join(t.TempDir(), "log.txt"))
+	if err := storage.AppendEntry(storage.Entry{Start: storage.UTCNow().Add(-3 * time.Hour), Text: "Deep work"}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	reminder := notify.NewReminder(notify.Rules{LongRunning: time.Hour, Summary: true})
+	notifier := &failingNotifier{fail: map[string]bool{"Still running: Deep work": true}}
+	if err := checkReminders(reminder, notifier, nil); err == nil {
+		t.Error("Expected the failed notification to be reported")
+	}
+	if len(notifier.sent) != 1 {
+		t.Fatalf("Expected the summary to be sent despite the failure, got %v", notifier.sent)
+	}
+
+	notifier.fail = nil
+	if err := checkReminders(reminder, notifier, nil); err != nil {
+		t.Fatalf("checkReminders failed: %v", err)
+	}
+	if len(notifier.sent) != 2 || notifier.sent[1] != "Still running: Deep work" {
+		t.Errorf("Expected only the failed notification to be sent again, got %v", notifier.sent)
+	}
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"lazytime/config"
+	"lazytime/storage"
+)
+
+// writeReportLog writes a morning of entries on 2024-01-01 (local time)
+// to a temporary log.
+func writeReportLog(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv(storage.LogEnvVar, filepath.Join(dir, "log.txt"))
+	t.Setenv(config.ConfigEnvVar, filepath.Join(dir, "config.json"))
+
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	add := func(startHour, startMinute, endHour, endMinute int, text string) {
+		start := day.Add(time.Duration(startHour)*time.Hour + time.Duration(startMinute)*time.Minute).UTC()
+		end := day.Add(time.Duration(endHour)*time.Hour + time.Duration(endMinute)*time.Minute).UTC()
+		if err := storage.AppendEntry(storage.Entry{Start: start, End: &end, Text: text}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	add(9, 0, 10, 0, "API #clientA client:acme ticket:ABC-1")
+	add(10, 0, 11, 30, "Web #clientB client:globex")
+	add(11, 30, 12, 0, "Docs #clientA client:Acme")
+	add(13, 0, 13, 15, "Lunch")
+}
+
+func TestCommandReportFiltersByAttribute(t *testing.T) {
+	writeReportLog(t)
+
+	var out bytes.Buffer
+	opts := ReportOptions{FromDate: "2024-01-01", ToDate: "2024-01-01", Attrs: []string{"client:acme"}}
+	if err := CommandReport(&out, opts); err != nil {
+		t.Fatalf("CommandReport failed: %v", err)
+	}
+	report := out.String()
+	if !strings.Contains(report, "- clientA: "+FormatDuration(90*time.Minute)) {
+		t.Errorf("Expected #clientA with both acme entries, got:\n%s", report)
+	}
+	if strings.Contains(report, "clientB") {
+		t.Errorf("Expected #clientB to be filtered out, got:\n%s", report)
+	}
+	if !strings.Contains(report, "Total: "+FormatDuration(90*time.Minute)) {
+		t.Errorf("Expected the filtered total, got:\n%s", report)
+	}
+
+	out.Reset()
+	opts.Attrs = []string{"client", "ticket=abc-1"}
+	if err := CommandReport(&out, opts); err != nil {
+		t.Fatalf("CommandReport failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Total: "+FormatDuration(time.Hour)) {
+		t.Errorf("Expected only the entry matching both filters, got:\n%s", out.String())
+	}
+
+	if err := CommandReport(&out, ReportOptions{Attrs: []string{":acme"}}); err == nil {
+		t.Error("Expected an error for a filter without a key")
+	}
+}
+
+func TestCommandReportGroupsByAttribute(t *testing.T) {
+	writeReportLog(t)
+
+	var out bytes.Buffer
+	opts := ReportOptions{FromDate: "2024-01-01", ToDate: "2024-01-01", GroupBy: "attr:Client"}
+	if err := CommandReport(&out, opts); err != nil {
+		t.Fatalf("CommandReport failed: %v", err)
+	}
+	want := strings.Join([]string{
+		"Report 2024-01-01 to 2024-01-01 by client",
+		"- (no client): " + FormatDuration(15*time.Minute),
+		"- acme: " + FormatDuration(90*time.Minute),
+		"- globex: " + FormatDuration(90*time.Minute),
+		"Total: " + FormatDuration(195*time.Minute),
+	}, "\n") + "\n"
+	if out.String() != want {
+		t.Errorf("Grouped report mismatch:\ngot:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	if err := CommandReport(&out, ReportOptions{GroupBy: "attr:"}); err == nil {
+		t.Error("Expected an error for --group-by without a key")
+	}
+}
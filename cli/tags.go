@@ -0,0 +1,299 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"lazytime/config"
+	"lazytime/storage"
+)
+
+// TagStat summarizes how a tag has been used across all entries.
+// Parent tags include the usage of their child tags.
+type TagStat struct {
+	Tag       string
+	Total     time.Duration
+	Entries   int
+	FirstUsed time.Time
+	LastUsed  time.Time
+}
+
+// CollectTagStats computes usage statistics for every tag in entries,
+// including parent tags of hierarchical tags. Tags are matched by
+// storage.TagKey, so case-insensitively, and keep the spelling they were
+// first seen with; an entry counts once toward each tag however it
+// spells it.
+func CollectTagStats(entries []storage.Entry, now time.Time) []TagStat {
+	stats := make(map[string]*TagStat)
+	for _, entry := range entries {
+		entryEnd := now
+		if entry.End != nil {
+			entryEnd = *entry.End
+		}
+		duration := ClampDuration(entry, time.Time{}, now, now)
+		seen := make(map[string]bool)
+		for _, path := range entry.TagPaths() {
+			key := storage.TagKey(path)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			stat, exists := stats[key]
+			if !exists {
+				stat = &TagStat{Tag: path, FirstUsed: entry.Start, LastUsed: entryEnd}
+				stats[key] = stat
+			}
+			stat.Total += duration
+			stat.Entries++
+			if entry.Start.Before(stat.FirstUsed) {
+				stat.FirstUsed = entry.Start
+			}
+			if entryEnd.After(stat.LastUsed) {
+				stat.LastUsed = entryEnd
+			}
+		}
+	}
+
+	var result []TagStat
+	for _, stat := range stats {
+		result = append(result, *stat)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return storage.CompareTags(result[i].Tag, result[j].Tag) < 0
+	})
+	return result
+}
+
+// CommandTagsList prints every tag with its total time, entry count and
+// first/last use. If unusedSince is set, only tags not used since that
+// date are listed.
+func CommandTagsList(out io.Writer, unusedSince string) error {
+	entries, err := storage.ReadEntries("")
+	if err != nil {
+		return fmt.Errorf("failed to read entries: %w", err)
+	}
+
+	cfg, err := config.Load("")
+	if err != nil {
+		return err
+	}
+	entries = storage.ApplyTagAliases(entries, cfg.TagAliases)
+
+	now := storage.LocalNow()
+	tz := now.Location()
+
+	var cutoff time.Time
+	if unusedSince != "" {
+		parsed, err := storage.ParseDate(unusedSince)
+		if err != nil {
+			return fmt.Errorf("invalid --unused-since date: %w", err)
+		}
+		cutoff = time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 0, 0, 0, 0, tz)
+	}
+
+	if len(storage.UniqueTags(entries)) == 0 {
+		fmt.Fprintln(out, "No tags used yet.")
+		return nil
+	}
+	stats := CollectTagStats(entries, storage.UTCNow())
+
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "TAG\tTOTAL\tENTRIES\tFIRST USED\tLAST USED")
+	listed := 0
+	for _, stat := range stats {
+		if !cutoff.IsZero() && !stat.LastUsed.Before(cutoff) {
+			continue
+		}
+		// Indent child tags when their parent is listed too
+		name := stat.Tag
+		if cutoff.IsZero() {
+			name = strings.Repeat("  ", storage.TagDepth(stat.Tag)) + storage.TagName(stat.Tag)
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%d\t%s\t%s\n",
+			name,
+			FormatDuration(stat.Total),
+			stat.Entries,
+			stat.FirstUsed.In(tz).Format("2006-01-02"),
+			stat.LastUsed.In(tz).Format("2006-01-02"),
+		)
+		listed++
+	}
+
+	if listed == 0 {
+		fmt.Fprintf(out, "No tags unused since %s.\n", cutoff.Format("2006-01-02"))
+		return nil
+	}
+	return writer.Flush()
+}
+
+// tagChange describes an entry whose text is rewritten by a tag operation.
+type tagChange struct {
+	index   int
+	oldText string
+	newText string
+}
+
+// planTagRewrite computes the entries affected by renaming each tag in
+// sources (and its child tags) to dest.
+func planTagRewrite(entries []storage.Entry, sources []string, dest string) []tagChange {
+	var changes []tagChange
+	for i, entry := range entries {
+		newText := storage.RewriteTags(entry.Text, func(tag string) string {
+			for _, source := range sources {
+				if renamed, ok := storage.ReplaceTagPrefix(tag, source, dest); ok {
+					return renamed
+				}
+			}
+			return tag
+		})
+		if newText != entry.Text {
+			changes = append(changes, tagChange{index: i, oldText: entry.Text, newText: newText})
+		}
+	}
+	return changes
+}
+
+// tagInUse reports whether any entry carries tag or one of its child tags.
+func tagInUse(entries []storage.Entry, tag string) bool {
+	for _, entry := range entries {
+		if entry.HasTagWithin(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// rewriteTags plans a tag rewrite on the entries under the log lock,
+// previews the changes and, unless dryRun is set, writes them. plan
+// returns the changes and their description.
+func rewriteTags(out io.Writer, dryRun bool, plan func(entries []storage.Entry) ([]tagChange, string, error)) error {
+	written := 0
+	err := storage.UpdateEntries("", func(entries []storage.Entry) ([]storage.Entry, error) {
+		changes, description, err := plan(entries)
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) == 0 {
+			fmt.Fprintf(out, "%s: no entries affected.\n", description)
+			return entries, nil
+		}
+
+		fmt.Fprintf(out, "%s affects %d entries:\n", description, len(changes))
+		for _, change := range changes {
+			startLocal := entries[change.index].Start.In(time.Local)
+			fmt.Fprintf(out, "  %s  - %s\n", startLocal.Format("2006-01-02 15:04"), change.oldText)
+			fmt.Fprintf(out, "  %s  + %s\n", strings.Repeat(" ", len("2006-01-02 15:04")), change.newText)
+		}
+
+		if dryRun {
+			fmt.Fprintln(out, "Dry run: no changes written.")
+			return entries, nil
+		}
+
+		for _, change := range changes {
+			entries[change.index].Text = change.newText
+		}
+		written = len(changes)
+		return entries, nil
+	})
+	if err != nil {
+		return err
+	}
+	if written > 0 {
+		fmt.Fprintf(out, "Updated %d entries.\n", written)
+	}
+	return nil
+}
+
+// CommandTagsRename renames a tag (and its child tags) across all entries.
+// Fails if the new tag is already in use; use CommandTagsMerge for that.
+func CommandTagsRename(out io.Writer, oldTag, newTag string, dryRun bool) error {
+	oldTag = strings.TrimPrefix(oldTag, "#")
+	newTag = strings.TrimPrefix(newTag, "#")
+	if storage.NormalizeTag(oldTag) == "" || storage.NormalizeTag(newTag) == "" {
+		return fmt.Errorf("tag names cannot be empty")
+	}
+
+	return rewriteTags(out, dryRun, func(entries []storage.Entry) ([]tagChange, string, error) {
+		if !tagInUse(entries, oldTag) {
+			return nil, "", fmt.Errorf("tag #%s is not used by any entry", oldTag)
+		}
+		if tagInUse(entries, newTag) {
+			return nil, "", fmt.Errorf("tag #%s already exists; use 'tags merge %s %s' to combine them", newTag, oldTag, newTag)
+		}
+		return planTagRewrite(entries, []string{oldTag}, newTag), fmt.Sprintf("Renaming #%s -> #%s", oldTag, newTag), nil
+	})
+}
+
+// CommandTagsMerge merges one or more source tags (and their child tags)
+// into dest across all entries.
+func CommandTagsMerge(out io.Writer, sources []string, dest string, dryRun bool) error {
+	dest = strings.TrimPrefix(dest, "#")
+	if storage.NormalizeTag(dest) == "" {
+		return fmt.Errorf("tag names cannot be empty")
+	}
+	var names []string
+	for i, source := range sources {
+		sources[i] = strings.TrimPrefix(source, "#")
+		if storage.NormalizeTag(sources[i]) == "" {
+			return fmt.Errorf("tag names cannot be empty")
+		}
+		if storage.IsTagWithin(dest, sources[i]) {
+			return fmt.Errorf("cannot merge #%s into its own child #%s", sources[i], dest)
+		}
+		names = append(names, "#"+sources[i])
+	}
+
+	return rewriteTags(out, dryRun, func(entries []storage.Entry) ([]tagChange, string, error) {
+		return planTagRewrite(entries, sources, dest), fmt.Sprintf("Merging %s -> #%s", strings.Join(names, ", "), dest), nil
+	})
+}
+
+// runTags lists tags or dispatches the tags subcommands.
+func runTags(out io.Writer, args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "--") {
+		var unusedSince string
+		for i := 0; i < len(args); i++ {
+			if args[i] == "--unused-since" {
+				if i+1 >= len(args) {
+					return fmt.Errorf("--unused-since requires a date value")
+				}
+				unusedSince = args[i+1]
+				i++
+			} else {
+				return fmt.Errorf("unknown tags option: %s", args[i])
+			}
+		}
+		return CommandTagsList(out, unusedSince)
+	}
+
+	subcommand := args[0]
+	dryRun := false
+	var positional []string
+	for _, arg := range args[1:] {
+		if arg == "--dry-run" {
+			dryRun = true
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+
+	switch subcommand {
+	case "rename":
+		if len(positional) != 2 {
+			return fmt.Errorf("usage: tags rename OLD NEW [--dry-run]")
+		}
+		return CommandTagsRename(out, positional[0], positional[1], dryRun)
+	case "merge":
+		if len(positional) < 2 {
+			return fmt.Errorf("usage: tags merge SOURCE... DEST [--dry-run]")
+		}
+		return CommandTagsMerge(out, positional[:len(positional)-1], positional[len(positional)-1], dryRun)
+	default:
+		return fmt.Errorf("unknown tags subcommand: %s", subcommand)
+	}
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"lazytime/storage"
+)
+
+func TestCollectTagStatsFoldsCase(t *testing.T) {
+	entry := func(day, hours int, text string) storage.Entry {
+		start := time.Date(2024, 1, day, 9, 0, 0, 0, time.UTC)
+		end := start.Add(time.Duration(hours) * time.Hour)
+		return storage.Entry{Start: start, End: &end, Text: text}
+	}
+	entries := []storage.Entry{
+		entry(1, 1, "Plan #Work"),
+		entry(2, 2, "Build #work/api"),
+		entry(3, 1, "Both #Work #WORK"),
+	}
+
+	stats := CollectTagStats(entries, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	if len(stats) != 2 {
+		t.Fatalf("Expected 2 tags, got %+v", stats)
+	}
+
+	work := stats[0]
+	if work.Tag != "Work" {
+		t.Errorf("Expected the first seen spelling, got %q", work.Tag)
+	}
+	if work.Total != 4*time.Hour || work.Entries != 3 {
+		t.Errorf("Expected 4h over 3 entries, got %s over %d", work.Total, work.Entries)
+	}
+	if !work.LastUsed.Equal(*entries[2].End) {
+		t.Errorf("Expected last use on the third entry, got %v", work.LastUsed)
+	}
+
+	api := stats[1]
+	if api.Tag != "work/api" || api.Total != 2*time.Hour || api.Entries != 1 {
+		t.Errorf("Unexpected child stat: %+v", api)
+	}
+}
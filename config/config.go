@@ -0,0 +1,166 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"lazytime/storage"
+)
+
+const ConfigEnvVar = "LAZYTIME_CONFIG"
+
+// Config holds user settings read from the config file.
+type Config struct {
+	// Rounding is a spec like "up:15m" (see storage.ParseRounding).
+	Rounding string `json:"rounding"`
+	// RoundingScope is "entry" or "aggregate".
+	RoundingScope string `json:"rounding_scope"`
+	// TagAliases maps alias tags to their canonical tag (without "#").
+	// Aliases are applied when entries are read for reports and the TUI.
+	TagAliases map[string]string `json:"tag_aliases"`
+	// WorkingHours is the daily window like "09:00-17:00" used for gaps.
+	WorkingHours string `json:"working_hours"`
+	// WorkingDays lists the weekdays ("mon", "tue", ...) used for gaps.
+	WorkingDays []string `json:"working_days"`
+	// Theme is the TUI theme: "auto" (default), "dark", "light",
+	// "high-contrast", "colorblind" or a .json theme file path (relative
+	// paths are relative to the config file).
+	Theme string `json:"theme"`
+	// Keys rebinds TUI actions, mapping action names ("new_entry", "help",
+	// ...) to key names like "e", "ctrl+n" or "space".
+	Keys map[string][]string `json:"keys"`
+	// IdleTimeout is how long without input (like "10m") counts as idle
+	// while an entry runs in the TUI; empty disables idle detection.
+	IdleTimeout string `json:"idle_timeout"`
+	// IdleCommand prints the desktop's idle time in milliseconds (like
+	// xprintidle). Without it, idle means no keys pressed in the TUI.
+	IdleCommand string `json:"idle_command"`
+	// Notifications configures the reminders sent by "lazytime remind".
+	Notifications NotificationConfig `json:"notifications"`
+}
+
+// NotificationConfig holds the reminder rules. Empty values turn a rule
+// off.
+type NotificationConfig struct {
+	// Command shows a notification, getting the title and body as its
+	// last two arguments. Empty means notify-send (osascript on macOS).
+	Command string `json:"command"`
+	// NotTracking reminds to start tracking after this long (like "15m")
+	// without a running entry during working hours.
+	NotTracking string `json:"not_tracking"`
+	// LongRunning warns once an entry has been running this long (like "4h").
+	LongRunning string `json:"long_running"`
+	// DailyGoal announces when this much (like "8h") is tracked in a day.
+	DailyGoal string `json:"daily_goal"`
+	// DailySummary sends a summary of the day at this local time ("HH:MM").
+	DailySummary string `json:"daily_summary"`
+}
+
+// DefaultConfigPath returns the config file path from environment variable
+// or defaults to config.json next to the log file.
+func DefaultConfigPath() string {
+	envValue := os.Getenv(ConfigEnvVar)
+	if envValue != "" {
+		return filepath.Clean(envValue)
+	}
+	return filepath.Join(filepath.Dir(storage.DefaultLogPath()), "config.json")
+}
+
+// Load reads the config file. A missing file yields the default config.
+func Load(path string) (Config, error) {
+	if path == "" {
+		path = DefaultConfigPath()
+	}
+
+	var cfg Config
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := json.Unmarshal(content, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// RoundingPolicy returns the configured rounding policy.
+func (c Config) RoundingPolicy() (storage.Rounding, error) {
+	policy, err := storage.ParseRounding(c.Rounding)
+	if err != nil {
+		return storage.Rounding{}, err
+	}
+	scope, err := storage.ParseRoundScope(c.RoundingScope)
+	if err != nil {
+		return storage.Rounding{}, err
+	}
+	policy.Scope = scope
+	return policy, nil
+}
+
+// IdleThreshold returns the configured idle timeout, 0 if idle detection
+// is disabled.
+func (c Config) IdleThreshold() (time.Duration, error) {
+	if c.IdleTimeout == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(c.IdleTimeout)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("invalid idle_timeout %q: expected a positive duration like 10m", c.IdleTimeout)
+	}
+	return timeout, nil
+}
+
+// ReminderThresholds returns the configured not tracking, long running
+// and daily goal reminder durations, 0 for each rule that is off.
+func (c Config) ReminderThresholds() (notTracking, longRunning, dailyGoal time.Duration, err error) {
+	n := c.Notifications
+	if notTracking, err = parseRuleDuration("not_tracking", n.NotTracking); err != nil {
+		return 0, 0, 0, err
+	}
+	if longRunning, err = parseRuleDuration("long_running", n.LongRunning); err != nil {
+		return 0, 0, 0, err
+	}
+	if dailyGoal, err = parseRuleDuration("daily_goal", n.DailyGoal); err != nil {
+		return 0, 0, 0, err
+	}
+	return notTracking, longRunning, dailyGoal, nil
+}
+
+// DailySummaryTime returns the time of day of the daily summary as an
+// offset from midnight; ok is false if the summary is off.
+func (c Config) DailySummaryTime() (at time.Duration, ok bool, err error) {
+	value := c.Notifications.DailySummary
+	if value == "" {
+		return 0, false, nil
+	}
+	hour, minute, err := storage.ParseTimeOfDay(value)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid notifications.daily_summary %q: %w", value, err)
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, true, nil
+}
+
+// parseRuleDuration parses a notification rule duration; empty is 0.
+func parseRuleDuration(name, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid notifications.%s %q: expected a positive duration like 30m", name, value)
+	}
+	return duration, nil
+}
+
+// WorkingSchedule returns the configured working hours, defaulting to
+// 09:00-17:00 Monday to Friday.
+func (c Config) WorkingSchedule() (storage.WorkingHours, error) {
+	return storage.ParseWorkingHours(c.WorkingHours, c.WorkingDays)
+}
@@ -0,0 +1,146 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"lazytime/storage"
+)
+
+// dialTimeout bounds how long connecting to the daemon may take.
+const dialTimeout = time.Second
+
+// updateAttempts is how often Update tries again when the log changed
+// between reading it and the daemon applying the update.
+const updateAttempts = 3
+
+// dial connects to the daemon, returning ErrNotRunning if nothing
+// listens on path.
+func dial(path string) (net.Conn, error) {
+	conn, err := net.DialTimeout("unix", path, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotRunning, err)
+	}
+	return conn, nil
+}
+
+// Call sends a request to the daemon listening on path and returns its
+// response.
+func Call(path string, req Request) (Response, error) {
+	conn, err := dial(path)
+	if err != nil {
+		return Response{}, err
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return Response{}, fmt.Errorf("failed to send request: %w", err)
+	}
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return Response{}, fmt.Errorf("failed to read response: %w", err)
+	}
+	return resp, nil
+}
+
+// Update changes the entries of the default log with update, which gets
+// the current entries and returns the new ones. When a daemon listens on
+// path the change is sent to it, so that it stays the only writer, and
+// update runs again on the fresh entries if the log changed in between;
+// otherwise the log file is updated directly under its lock. A daemon
+// serving another log refuses the change. If the log still changed on the
+// last attempt, the error wraps ErrConflict.
+func Update(path string, update func(entries []storage.Entry) ([]storage.Entry, error)) error {
+	logPath := storage.DefaultLogPath()
+	for attempt := 1; ; attempt++ {
+		entries, err := storage.ReadEntries(logPath)
+		if err != nil {
+			return err
+		}
+		// Taken before update, which may change entries in place
+		base := storage.LogVersion(entries)
+		updated, err := update(entries)
+		if err != nil {
+			return err
+		}
+		lines := make([]string, len(updated))
+		for i, entry := range updated {
+			lines[i] = storage.FormatEntry(entry)
+		}
+
+		resp, err := Call(path, Request{Command: "update", Log: logPath, Base: base, Entries: lines})
+		if errors.Is(err, ErrNotRunning) {
+			return storage.UpdateEntries(logPath, update)
+		}
+		if err != nil {
+			return err
+		}
+		if resp.Conflict {
+			if attempt < updateAttempts {
+				continue
+			}
+			return fmt.Errorf("%w (tried %d times)", ErrConflict, updateAttempts)
+		}
+		if !resp.OK {
+			return errors.New(resp.Error)
+		}
+		return nil
+	}
+}
+
+// Subscription receives the daemon's change events.
+type Subscription struct {
+	Events <-chan Event // Closed when the connection ends
+	conn   net.Conn
+	done   chan struct{}
+}
+
+// Close ends the subscription.
+func (s *Subscription) Close() error {
+	close(s.done)
+	return s.conn.Close()
+}
+
+// Subscribe subscribes to the change events of the daemon listening on
+// path.
+func Subscribe(path string) (*Subscription, error) {
+	conn, err := dial(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.NewEncoder(conn).Encode(Request{Command: "subscribe"}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	decoder := json.NewDecoder(conn)
+	var resp Response
+	if err := decoder.Decode(&resp); err != nil || !resp.OK {
+		conn.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to subscribe: %w", err)
+		}
+		return nil, fmt.Errorf("failed to subscribe: %s", resp.Error)
+	}
+
+	events := make(chan Event)
+	done := make(chan struct{})
+	go func() {
+		defer close(events)
+		for {
+			var event Event
+			if decoder.Decode(&event) != nil {
+				return
+			}
+			select {
+			case events <- event:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return &Subscription{Events: events, conn: conn, done: done}, nil
+}
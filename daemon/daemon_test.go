@@ -0,0 +1,328 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"lazytime/storage"
+)
+
+func TestServerRunsCommandsAndBroadcastsChanges(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "log.txt")
+	socketPath := filepath.Join(dir, "daemon.sock")
+
+	server := &Server{
+		LogPath:  logPath,
+		Commands: map[string]bool{"start": true},
+		Handle: func(command string, args []string, out io.Writer) error {
+			entry := storage.Entry{Start: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), Text: args[0]}
+			if err := storage.AppendEntry(entry, logPath); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Started: %s\n", args[0])
+			return nil
+		},
+	}
+	listener, err := Listen(socketPath)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go server.Serve(listener)
+	defer listener.Close()
+
+	if info, err := os.Stat(socketPath); err != nil || info.Mode().Perm()&0077 != 0 {
+		t.Errorf("Expected a socket only the owner may use, got %v (%v)", info.Mode(), err)
+	}
+	if _, err := Listen(socketPath); err == nil {
+		t.Error("Expected a second daemon to be refused")
+	}
+
+	sub, err := Subscribe(socketPath)
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	defer sub.Close()
+
+	resp, err := Call(socketPath, Request{Command: "start", Args: []string{"Write docs #project"}})
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if !resp.OK || resp.Output != "Started: Write docs #project\n" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+	if resp.Running == nil || resp.Running.Text != "Write docs #project" || len(resp.Running.Tags) != 1 {
+		t.Errorf("Expected the started entry to be running, got %+v", resp.Running)
+	}
+	expectEvent(t, sub, true)
+
+	// Changes made by other processes are broadcast too
+	if err := os.WriteFile(logPath, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	expectEvent(t, sub, false)
+
+	resp, err = Call(socketPath, Request{Command: "report"})
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if resp.OK || resp.Error != "unknown command: report" {
+		t.Errorf("Expected unknown command error, got %+v", resp)
+	}
+}
+
+func TestUpdateThroughDaemon(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "log.txt")
+	socketPath := filepath.Join(dir, "daemon.sock")
+	t.Setenv(storage.LogEnvVar, logPath)
+
+	var updates atomic.Int32
+	server := &Server{
+		LogPath: logPath,
+		Logf: func(format string, args ...any) {
+			updates.Add(1)
+		},
+	}
+	listener, err := Listen(socketPath)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go server.Serve(listener)
+	defer listener.Close()
+
+	first := storage.Entry{Start: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), Text: "First"}
+	other := storage.Entry{Start: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), Text: "Other"}
+	second := storage.Entry{Start: time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC), Text: "Second"}
+	if err := Update(socketPath, func(entries []storage.Entry) ([]storage.Entry, error) {
+		return append(entries, first), nil
+	}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	// Another process writing in between makes the update run again on
+	// the fresh entries
+	calls := 0
+	err = Update(socketPath, func(entries []storage.Entry) ([]storage.Entry, error) {
+		calls++
+		if calls == 1 {
+			if err := storage.AppendEntry(other, logPath); err != nil {
+				return nil, err
+			}
+		}
+		return append(entries, second), nil
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected the update to be retried once, got %d calls", calls)
+	}
+	if n := updates.Load(); n != 3 {
+		t.Errorf("Expected the daemon to handle 3 updates, got %d", n)
+	}
+
+	entries, err := storage.ReadEntries(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || entries[0].Text != "First" || entries[1].Text != "Other" || entries[2].Text != "Second" {
+		t.Errorf("Expected all three entries, got %v", entries)
+	}
+
+	resp, err := Call(socketPath, Request{Command: "update", Base: storage.LogVersion(nil)})
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if resp.OK || !resp.Conflict {
+		t.Errorf("Expected an outdated update to conflict, got %+v", resp)
+	}
+}
+
+func TestUpdateGivesUpOnConflicts(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "log.txt")
+	socketPath := filepath.Join(dir, "daemon.sock")
+	t.Setenv(storage.LogEnvVar, logPath)
+
+	listener, err := Listen(socketPath)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go (&Server{LogPath: logPath}).Serve(listener)
+	defer listener.Close()
+
+	// Another process writes between every read and update
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	calls := 0
+	err = Update(socketPath, func(entries []storage.Entry) ([]storage.Entry, error) {
+		calls++
+		other := storage.Entry{Start: start.Add(time.Duration(calls) * time.Hour), Text: "Other"}
+		if err := storage.AppendEntry(other, logPath); err != nil {
+			return nil, err
+		}
+		return append(entries, storage.Entry{Start: start, Text: "Mine"}), nil
+	})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("Expected ErrConflict, got %v", err)
+	}
+	if calls != updateAttempts {
+		t.Errorf("Expected %d attempts, got %d", updateAttempts, calls)
+	}
+	entries, err := storage.ReadEntries(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != updateAttempts {
+		t.Errorf("Expected only the other process's entries, got %v", entries)
+	}
+}
+
+func TestDaemonRefusesAnotherLog(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "log.txt")
+	socketPath := filepath.Join(dir, "daemon.sock")
+
+	server := &Server{
+		LogPath:  logPath,
+		Commands: map[string]bool{"status": true},
+		Handle: func(command string, args []string, out io.Writer) error {
+			return nil
+		},
+	}
+	listener, err := Listen(socketPath)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go server.Serve(listener)
+	defer listener.Close()
+
+	resp, err := Call(socketPath, Request{Command: "status", Log: filepath.Join(dir, ".", "log.txt")})
+	if err != nil || !resp.OK {
+		t.Errorf("Expected the daemon's own log to be accepted, got %+v (%v)", resp, err)
+	}
+	resp, err = Call(socketPath, Request{Command: "status", Log: filepath.Join(dir, "other.txt")})
+	if err != nil || resp.OK || !strings.Contains(resp.Error, "other.txt") {
+		t.Errorf("Expected a command for another log to be refused, got %+v (%v)", resp, err)
+	}
+
+	// A client using another log does not change the daemon's
+	otherPath := filepath.Join(dir, "other.txt")
+	t.Setenv(storage.LogEnvVar, otherPath)
+	err = Update(socketPath, func(entries []storage.Entry) ([]storage.Entry, error) {
+		return append(entries, storage.Entry{Start: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), Text: "First"}), nil
+	})
+	if err == nil {
+		t.Error("Expected the update of another log to be refused")
+	}
+	for _, path := range []string{logPath, otherPath} {
+		if entries, _ := storage.ReadEntries(path); len(entries) != 0 {
+			t.Errorf("Expected %s to stay empty, got %v", path, entries)
+		}
+	}
+}
+
+func TestUpdateLargeLogThroughDaemon(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "log.txt")
+	socketPath := filepath.Join(dir, "daemon.sock")
+	t.Setenv(storage.LogEnvVar, logPath)
+
+	// 1,500 entries make a log of about 130KB, over bufio.Scanner's limit
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	entries := make([]storage.Entry, 1500)
+	for i := range entries {
+		entryStart := start.Add(time.Duration(i) * time.Hour)
+		end := entryStart.Add(30 * time.Minute)
+		entries[i] = storage.Entry{Start: entryStart, End: &end, Text: fmt.Sprintf("Entry %d with a longer description #project/part%d", i, i%10)}
+	}
+	if err := storage.WriteEntries(entries, logPath); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(logPath); err != nil || info.Size() <= 64*1024 {
+		t.Fatalf("Expected a log over 64KB, got %v (%v)", info.Size(), err)
+	}
+
+	server := &Server{LogPath: logPath}
+	listener, err := Listen(socketPath)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go server.Serve(listener)
+	defer listener.Close()
+
+	sub, err := Subscribe(socketPath)
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	defer sub.Close()
+
+	// Rewriting the first entry sends the whole log; the running entry's
+	// long text makes the change event itself over 64KB
+	long := strings.Repeat("x", 70*1024)
+	err = Update(socketPath, func(current []storage.Entry) ([]storage.Entry, error) {
+		current[0].Text = "Renamed #project"
+		return append(current, storage.Entry{Start: start.Add(2000 * time.Hour), Text: long}), nil
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	select {
+	case event := <-sub.Events:
+		if event.Running == nil || event.Running.Text != long {
+			t.Errorf("Expected the long running entry in the event, got %+v", event.Running)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Expected a change event")
+	}
+
+	got, err := storage.ReadEntries(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1501 || got[0].Text != "Renamed #project" || got[1500].Text != long {
+		t.Errorf("Expected the renamed log with the running entry, got %d entries", len(got))
+	}
+}
+
+func TestUpdateWithoutDaemon(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "log.txt")
+	t.Setenv(storage.LogEnvVar, logPath)
+
+	entry := storage.Entry{Start: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), Text: "First"}
+	if err := Update(filepath.Join(dir, "daemon.sock"), func(entries []storage.Entry) ([]storage.Entry, error) {
+		return append(entries, entry), nil
+	}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if entries, _ := storage.ReadEntries(logPath); len(entries) != 1 || entries[0].Text != "First" {
+		t.Errorf("Expected the entry to be written directly, got %v", entries)
+	}
+}
+
+func TestCallWithoutDaemon(t *testing.T) {
+	_, err := Call(filepath.Join(t.TempDir(), "daemon.sock"), Request{Command: "status"})
+	if !errors.Is(err, ErrNotRunning) {
+		t.Errorf("Expected ErrNotRunning, got %v", err)
+	}
+}
+
+func expectEvent(t *testing.T, sub *Subscription, running bool) {
+	t.Helper()
+	select {
+	case event := <-sub.Events:
+		if event.Event != "change" || (event.Running != nil) != running {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Timed out waiting for a change event")
+	}
+}
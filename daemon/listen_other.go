@@ -0,0 +1,11 @@
+//go:build !unix
+
+package daemon
+
+import "net"
+
+// listenPrivate listens on a Unix socket. Without a umask, the socket
+// gets the permissions of its directory.
+func listenPrivate(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
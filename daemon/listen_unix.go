@@ -0,0 +1,18 @@
+//go:build unix
+
+package daemon
+
+import (
+	"net"
+	"syscall"
+)
+
+// listenPrivate listens on a Unix socket that only the owner may connect
+// to. The umask is narrowed while the socket is created, so it is never
+// reachable with wider permissions; this briefly affects files created
+// by other goroutines too, which the daemon does not do while starting.
+func listenPrivate(path string) (net.Listener, error) {
+	old := syscall.Umask(0077)
+	defer syscall.Umask(old)
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,93 @@
+// Package daemon serves the log over a Unix socket so that one process
+// owns the store, and notifies subscribers when entries change.
+//
+// The protocol is newline-delimited JSON: each line a client sends is a
+// Request and the daemon answers each with a Response line. After a
+// "subscribe" request the connection streams an Event line per change.
+// An "update" request replaces the entries with the ones it carries,
+// provided the log is still at the version the client read, which lets
+// clients like the TUI make arbitrary edits through the daemon.
+package daemon
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"lazytime/storage"
+)
+
+// SocketEnvVar overrides the socket path.
+const SocketEnvVar = "LAZYTIME_SOCKET"
+
+// ErrNotRunning is returned by the client when no daemon is listening.
+var ErrNotRunning = errors.New("daemon is not running")
+
+// ErrConflict is returned by Update when the log kept changing between
+// reading it and the daemon applying the update, and rejects an update
+// made on an outdated version of the log in the daemon.
+var ErrConflict = errors.New("the log changed since it was read")
+
+// Request is a command sent to the daemon. Args are the command-line
+// arguments following the command, like ["Write docs #project", "--at",
+// "09:00"] for start. Log is the log file the client means; the daemon
+// refuses requests for another log than its own, and an empty Log means
+// the daemon's. An update carries the new log lines in Entries and the
+// storage.LogVersion of the entries it was made on in Base.
+type Request struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+	Log     string   `json:"log,omitempty"`
+	Base    string   `json:"base,omitempty"`
+	Entries []string `json:"entries,omitempty"`
+}
+
+// Response answers a request. Output is what the command printed and
+// Running is the running entry after the command, if any. Conflict is set
+// when an update was rejected because the log changed since Base.
+type Response struct {
+	OK       bool       `json:"ok"`
+	Error    string     `json:"error,omitempty"`
+	Conflict bool       `json:"conflict,omitempty"`
+	Output   string     `json:"output,omitempty"`
+	Running  *EntryInfo `json:"running"`
+}
+
+// Event is sent to subscribers whenever the log changes, by a command or
+// by another process writing the file.
+type Event struct {
+	Event   string     `json:"event"` // Always "change"
+	Running *EntryInfo `json:"running"`
+}
+
+// EntryInfo describes an entry for clients.
+type EntryInfo struct {
+	Start time.Time  `json:"start"`
+	End   *time.Time `json:"end,omitempty"`
+	Text  string     `json:"text"`
+	Tags  []string   `json:"tags"`
+}
+
+// runningEntry returns the running entry, nil if none.
+func runningEntry(entries []storage.Entry) *EntryInfo {
+	idx := storage.FindOpen(entries)
+	if idx == -1 {
+		return nil
+	}
+	entry := entries[idx]
+	tags := entry.Tags()
+	if tags == nil {
+		tags = []string{}
+	}
+	return &EntryInfo{Start: entry.Start, End: entry.End, Text: entry.Text, Tags: tags}
+}
+
+// SocketPath returns the socket path from the environment variable, or
+// daemon.sock next to the log file.
+func SocketPath() string {
+	if envValue := os.Getenv(SocketEnvVar); envValue != "" {
+		return filepath.Clean(envValue)
+	}
+	return filepath.Join(filepath.Dir(storage.DefaultLogPath()), "daemon.sock")
+}
@@ -0,0 +1,313 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"lazytime/storage"
+)
+
+// pollInterval is how often the log file is checked for changes made by
+// other processes.
+const pollInterval = time.Second
+
+// subscriberBuffer is how many events a subscriber may fall behind before
+// it is disconnected.
+const subscriberBuffer = 16
+
+// Handler runs a command with its arguments, printing its output to out.
+type Handler func(command string, args []string, out io.Writer) error
+
+// Server runs commands on behalf of clients, one at a time, and
+// broadcasts change events to subscribers.
+type Server struct {
+	LogPath  string               // Log file watched for changes
+	Commands map[string]bool      // Commands clients may run
+	Handle   Handler              // Runs the commands
+	Logf     func(string, ...any) // Logs requests; nil discards
+
+	mu    sync.Mutex // Serializes commands and log checks
+	stamp logStamp   // Log file state of the last broadcast
+
+	subsMu sync.Mutex
+	subs   map[chan Event]bool
+}
+
+// logStamp identifies a version of the log file.
+type logStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// readLogStamp returns the stamp of the log file, zero if it is missing.
+func readLogStamp(path string) logStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return logStamp{}
+	}
+	return logStamp{modTime: info.ModTime(), size: info.Size()}
+}
+
+// Listen listens on the socket path, replacing a stale socket left by a
+// daemon that did not shut down. It fails if a daemon is already running.
+// Only the owner may connect to the socket.
+func Listen(path string) (net.Listener, error) {
+	if conn, err := net.DialTimeout("unix", path, dialTimeout); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("a daemon is already listening on %s", path)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+	}
+	listener, err := listenPrivate(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	return listener, nil
+}
+
+// Serve accepts connections until the listener is closed, checking the
+// log file for changes in the background.
+func (s *Server) Serve(listener net.Listener) error {
+	s.stamp = readLogStamp(s.LogPath)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.mu.Lock()
+				s.checkLog()
+				s.mu.Unlock()
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				s.closeSubscribers()
+				return nil
+			}
+			return err
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// serveConn answers the requests of one connection.
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+	// A decoder has no line length limit, so updates may carry a long log
+	decoder := json.NewDecoder(conn)
+	encoder := json.NewEncoder(conn)
+	for {
+		var req Request
+		if err := decoder.Decode(&req); err != nil {
+			// The rest of the stream cannot be parsed after an invalid request
+			if !errors.Is(err, io.EOF) {
+				encoder.Encode(Response{Error: "invalid request: " + err.Error()})
+			}
+			return
+		}
+		if req.Command == "subscribe" {
+			s.stream(conn, encoder)
+			return
+		}
+		var resp Response
+		if err := s.checkLogPath(req.Log); err != nil {
+			resp = Response{Error: err.Error()}
+		} else if req.Command == "update" {
+			resp = s.update(req)
+		} else {
+			resp = s.run(req)
+		}
+		if err := encoder.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// checkLogPath returns an error if a request names another log file than
+// the one the server owns.
+func (s *Server) checkLogPath(path string) error {
+	if path == "" {
+		return nil
+	}
+	want, err := filepath.Abs(s.LogPath)
+	if err != nil {
+		return err
+	}
+	got, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return fmt.Errorf("the daemon serves the log %s, not %s", want, got)
+	}
+	return nil
+}
+
+// run runs a request's command and checks the log for changes it made.
+func (s *Server) run(req Request) Response {
+	if !s.Commands[req.Command] {
+		return Response{Error: "unknown command: " + req.Command}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.logf("%s %q", req.Command, req.Args)
+	var out bytes.Buffer
+	err := s.Handle(req.Command, req.Args, &out)
+	running := s.checkLog()
+	if err != nil {
+		return Response{Error: err.Error(), Output: out.String(), Running: running}
+	}
+	return Response{OK: true, Output: out.String(), Running: running}
+}
+
+// update replaces the entries with the ones of an update request, unless
+// the log changed since the version the client read.
+func (s *Server) update(req Request) Response {
+	entries := make([]storage.Entry, len(req.Entries))
+	for i, line := range req.Entries {
+		entry, err := storage.ParseEntry(line)
+		if err != nil {
+			return Response{Error: "invalid entry: " + err.Error()}
+		}
+		entries[i] = entry
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.logf("update (%d entries)", len(entries))
+	err := storage.UpdateEntries(s.LogPath, func(current []storage.Entry) ([]storage.Entry, error) {
+		if storage.LogVersion(current) != req.Base {
+			return nil, ErrConflict
+		}
+		return entries, nil
+	})
+	running := s.checkLog()
+	if err != nil {
+		return Response{Error: err.Error(), Conflict: errors.Is(err, ErrConflict), Running: running}
+	}
+	return Response{OK: true, Running: running}
+}
+
+// checkLog reads the log, broadcasting a change event if the file changed
+// since the last check, and returns the running entry. The caller holds
+// s.mu.
+func (s *Server) checkLog() *EntryInfo {
+	stamp := readLogStamp(s.LogPath)
+	entries, err := storage.ReadEntries(s.LogPath)
+	if err != nil {
+		s.logf("failed to read entries: %v", err)
+		return nil
+	}
+	running := runningEntry(entries)
+	if stamp != s.stamp {
+		s.stamp = stamp
+		s.broadcast(Event{Event: "change", Running: running})
+	}
+	return running
+}
+
+// stream sends change events to a subscriber until it disconnects.
+func (s *Server) stream(conn net.Conn, encoder *json.Encoder) {
+	s.mu.Lock()
+	running := s.checkLog()
+	events := s.subscribe()
+	s.mu.Unlock()
+	defer s.unsubscribe(events)
+
+	if err := encoder.Encode(Response{OK: true, Running: running}); err != nil {
+		return
+	}
+
+	// Reading fails once the client disconnects
+	gone := make(chan struct{})
+	go func() {
+		io.Copy(io.Discard, conn)
+		close(gone)
+	}()
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := encoder.Encode(event); err != nil {
+				return
+			}
+		case <-gone:
+			return
+		}
+	}
+}
+
+// subscribe registers a subscriber.
+func (s *Server) subscribe() chan Event {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	if s.subs == nil {
+		s.subs = make(map[chan Event]bool)
+	}
+	events := make(chan Event, subscriberBuffer)
+	s.subs[events] = true
+	return events
+}
+
+// unsubscribe removes a subscriber unless it was already dropped.
+func (s *Server) unsubscribe(events chan Event) {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	if s.subs[events] {
+		delete(s.subs, events)
+		close(events)
+	}
+}
+
+// broadcast sends an event to every subscriber, dropping the ones that
+// fell too far behind.
+func (s *Server) broadcast(event Event) {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	for events := range s.subs {
+		select {
+		case events <- event:
+		default:
+			delete(s.subs, events)
+			close(events)
+		}
+	}
+}
+
+// closeSubscribers disconnects every subscriber.
+func (s *Server) closeSubscribers() {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	for events := range s.subs {
+		delete(s.subs, events)
+		close(events)
+	}
+}
+
+func (s *Server) logf(format string, args ...any) {
+	if s.Logf != nil {
+		s.Logf(format, args...)
+	}
+}
@@ -13,7 +13,7 @@ func main() {
 
 	if len(args) == 0 {
 		fmt.Fprintf(os.Stderr, "Usage: lazytime <command> [args...]\n")
-		fmt.Fprintf(os.Stderr, "Commands: start, stop, add, status, report, tui\n")
+		fmt.Fprintf(os.Stderr, "Commands: start, stop, add, status, report, gaps, tags, remind, daemon, tui\n")
 		os.Exit(1)
 	}
 
@@ -0,0 +1,63 @@
+// Package notify shows desktop notifications and decides when lazytime
+// reminds you to start or stop tracking.
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Notifier shows a desktop notification.
+type Notifier interface {
+	Notify(title, body string) error
+}
+
+// CommandNotifier runs a command with the title and body appended as its
+// last two arguments, like notify-send.
+type CommandNotifier struct {
+	Command []string
+}
+
+// Notify runs the command.
+func (n CommandNotifier) Notify(title, body string) error {
+	args := append(append([]string{}, n.Command[1:]...), title, body)
+	if out, err := exec.Command(n.Command[0], args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", n.Command[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// osascriptNotifier shows notifications through AppleScript on macOS.
+type osascriptNotifier struct{}
+
+// Notify runs osascript.
+func (osascriptNotifier) Notify(title, body string) error {
+	script := "display notification " + strconv.Quote(body) + " with title " + strconv.Quote(title)
+	if out, err := exec.Command("osascript", "-e", script).CombinedOutput(); err != nil {
+		return fmt.Errorf("osascript: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// New returns the notifier running command (split on spaces). An empty
+// command picks the platform's default: notify-send on Linux and BSD,
+// which talks to the D-Bus notification service, and osascript on macOS.
+func New(command string) (Notifier, error) {
+	if fields := strings.Fields(command); len(fields) > 0 {
+		return CommandNotifier{Command: fields}, nil
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return osascriptNotifier{}, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return nil, fmt.Errorf("notify-send not found; install libnotify or set a notification command")
+		}
+		return CommandNotifier{Command: []string{"notify-send", "--app-name=lazytime"}}, nil
+	default:
+		return nil, fmt.Errorf("no default notifier on %s; set a notification command", runtime.GOOS)
+	}
+}
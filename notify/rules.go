@@ -0,0 +1,229 @@
+package notify
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"lazytime/storage"
+)
+
+// Rules configures when reminders are sent. Zero durations turn a rule
+// off.
+type Rules struct {
+	NotTracking  time.Duration        // Nothing running this long within working hours
+	LongRunning  time.Duration        // An entry running this long
+	DailyGoal    time.Duration        // Tracked this much today
+	Summary      bool                 // Send a summary of the day at SummaryAt
+	SummaryAt    time.Duration        // Offset from local midnight
+	WorkingHours storage.WorkingHours // Window of the not tracking rule
+}
+
+// Enabled reports whether any rule is on.
+func (r Rules) Enabled() bool {
+	return r.NotTracking > 0 || r.LongRunning > 0 || r.DailyGoal > 0 || r.Summary
+}
+
+// rule identifies the rule a notification comes from.
+type rule int
+
+const (
+	notTrackingRule rule = iota + 1
+	longRunningRule
+	dailyGoalRule
+	summaryRule
+)
+
+// Notification is a reminder to show.
+type Notification struct {
+	Title string
+	Body  string
+
+	rule   rule
+	at     time.Time // Check time, or start of the long running entry
+	dayKey string    // Day (YYYY-MM-DD) of a goal or summary reminder
+}
+
+// Reminder checks the rules against the log and remembers what was sent,
+// so each reminder is sent once: the not tracking reminder once per
+// NotTracking interval, the long running one once per entry and the goal
+// and summary once per day. Check only reports what is due; Sent records
+// a delivered reminder, so one that failed to send is reported again.
+type Reminder struct {
+	Rules Rules
+
+	notTrackingSent time.Time // Last not tracking reminder
+	longRunningSent time.Time // Start of the entry last reminded about
+	goalSent        string    // Day (YYYY-MM-DD) of the last goal reminder
+	summarySent     string    // Day (YYYY-MM-DD) of the last summary
+}
+
+// NewReminder returns a reminder for rules that has not sent anything.
+func NewReminder(rules Rules) *Reminder {
+	return &Reminder{Rules: rules}
+}
+
+// Check returns the reminders due at now (UTC), evaluating days and
+// working hours in tz. Call Sent for each one delivered.
+func (r *Reminder) Check(entries []storage.Entry, now time.Time, tz *time.Location) []Notification {
+	var notifications []Notification
+	nowLocal := now.In(tz)
+	day := time.Date(nowLocal.Year(), nowLocal.Month(), nowLocal.Day(), 0, 0, 0, 0, tz)
+	dayKey := day.Format("2006-01-02")
+	open := storage.FindOpen(entries)
+
+	if r.Rules.NotTracking > 0 && open == -1 && r.Rules.WorkingHours.Days[day.Weekday()] {
+		windowStart := day.Add(r.Rules.WorkingHours.Start)
+		windowEnd := day.Add(r.Rules.WorkingHours.End)
+		if !nowLocal.Before(windowStart) && nowLocal.Before(windowEnd) {
+			idleSince := windowStart.UTC()
+			for _, entry := range entries {
+				if entry.End != nil && entry.End.After(idleSince) && !entry.End.After(now) {
+					idleSince = *entry.End
+				}
+			}
+			since := idleSince
+			if r.notTrackingSent.After(since) {
+				since = r.notTrackingSent
+			}
+			if now.Sub(since) >= r.Rules.NotTracking {
+				notifications = append(notifications, Notification{
+					Title: "Not tracking",
+					Body:  "Nothing has been tracked for " + formatDuration(now.Sub(idleSince)) + ".",
+					rule:  notTrackingRule,
+					at:    now,
+				})
+			}
+		}
+	}
+
+	if r.Rules.LongRunning > 0 && open != -1 {
+		entry := entries[open]
+		if entry.Duration(now) >= r.Rules.LongRunning && !entry.Start.Equal(r.longRunningSent) {
+			notifications = append(notifications, Notification{
+				Title: "Still running: " + entry.Text,
+				Body:  "Running for " + formatDuration(entry.Duration(now)) + ". Forgot to stop it?",
+				rule:  longRunningRule,
+				at:    entry.Start,
+			})
+		}
+	}
+
+	total, tags := trackedOn(entries, day, now)
+	if r.Rules.DailyGoal > 0 && total >= r.Rules.DailyGoal && r.goalSent != dayKey {
+		notifications = append(notifications, Notification{
+			Title:  "Daily goal reached",
+			Body:   "Tracked " + formatDuration(total) + " today (goal " + formatDuration(r.Rules.DailyGoal) + ").",
+			rule:   dailyGoalRule,
+			dayKey: dayKey,
+		})
+	}
+
+	if r.Rules.Summary && !nowLocal.Before(day.Add(r.Rules.SummaryAt)) && r.summarySent != dayKey {
+		notifications = append(notifications, Notification{
+			Title:  "Today: " + formatDuration(total),
+			Body:   summarizeTags(tags),
+			rule:   summaryRule,
+			dayKey: dayKey,
+		})
+	}
+	return notifications
+}
+
+// Sent records that a notification returned by Check was delivered, so it
+// is not sent again.
+func (r *Reminder) Sent(notification Notification) {
+	switch notification.rule {
+	case notTrackingRule:
+		r.notTrackingSent = notification.at
+	case longRunningRule:
+		r.longRunningSent = notification.at
+	case dailyGoalRule:
+		r.goalSent = notification.dayKey
+	case summaryRule:
+		r.summarySent = notification.dayKey
+	}
+}
+
+// trackedOn returns the time tracked on the local day starting at day,
+// in total and per top-level tag ("(untagged)" for entries without tags).
+// Tags are matched case-insensitively and named as first seen.
+func trackedOn(entries []storage.Entry, day, now time.Time) (time.Duration, map[string]time.Duration) {
+	dayStart := day.UTC()
+	dayEnd := day.AddDate(0, 0, 1).UTC()
+	var total time.Duration
+	tags := make(map[string]time.Duration)
+	names := make(map[string]string)
+	for _, entry := range entries {
+		start := entry.Start
+		end := now
+		if entry.End != nil {
+			end = *entry.End
+		}
+		if start.Before(dayStart) {
+			start = dayStart
+		}
+		if end.After(dayEnd) {
+			end = dayEnd
+		}
+		if !end.After(start) {
+			continue
+		}
+		duration := end.Sub(start)
+		total += duration
+
+		// An entry counts once toward each top-level tag
+		seen := make(map[string]bool)
+		for _, tag := range entry.Tags() {
+			parts := storage.SplitTag(tag)
+			if len(parts) == 0 {
+				continue
+			}
+			top := parts[0]
+			key := strings.ToLower(top)
+			if _, ok := names[key]; !ok {
+				names[key] = "#" + top
+			}
+			if !seen[key] {
+				seen[key] = true
+				tags[names[key]] += duration
+			}
+		}
+		if len(seen) == 0 {
+			tags["(untagged)"] += duration
+		}
+	}
+	return total, tags
+}
+
+// summarizeTags lists the three tags with the most time.
+func summarizeTags(tags map[string]time.Duration) string {
+	if len(tags) == 0 {
+		return "Nothing tracked today."
+	}
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if tags[names[i]] != tags[names[j]] {
+			return tags[names[i]] > tags[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	var parts []string
+	for _, name := range names[:min(3, len(names))] {
+		parts = append(parts, name+" "+formatDuration(tags[name]))
+	}
+	if len(names) > 3 {
+		parts = append(parts, fmt.Sprintf("%d more", len(names)-3))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatDuration formats a duration as hours and minutes, like 2h05m.
+func formatDuration(d time.Duration) string {
+	minutes := int(d.Minutes())
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
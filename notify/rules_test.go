@@ -0,0 +1,119 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"lazytime/storage"
+)
+
+func at(hour, minute int) time.Time {
+	// Monday
+	return time.Date(2024, 1, 1, hour, minute, 0, 0, time.UTC)
+}
+
+func finished(start, end time.Time, text string) storage.Entry {
+	return storage.Entry{Start: start, End: &end, Text: text}
+}
+
+// deliver checks the reminder and records every notification as sent.
+func deliver(reminder *Reminder, entries []storage.Entry, now time.Time, tz *time.Location) []Notification {
+	notifications := reminder.Check(entries, now, tz)
+	for _, notification := range notifications {
+		reminder.Sent(notification)
+	}
+	return notifications
+}
+
+func TestReminderNotTracking(t *testing.T) {
+	reminder := NewReminder(Rules{NotTracking: 15 * time.Minute, WorkingHours: storage.DefaultWorkingHours})
+	entries := []storage.Entry{finished(at(9, 0), at(10, 0), "Standup #meetings")}
+
+	if got := deliver(reminder, entries, at(10, 10), time.UTC); len(got) != 0 {
+		t.Fatalf("Expected no reminder 10 minutes after the last entry, got %v", got)
+	}
+	if got := deliver(reminder, entries, at(10, 15), time.UTC); len(got) != 1 {
+		t.Fatalf("Expected a reminder 15 minutes after the last entry, got %v", got)
+	}
+	if got := deliver(reminder, entries, at(10, 20), time.UTC); len(got) != 0 {
+		t.Fatalf("Expected the reminder not to repeat within 15 minutes, got %v", got)
+	}
+	if got := deliver(reminder, entries, at(10, 30), time.UTC); len(got) != 1 {
+		t.Fatalf("Expected the reminder to repeat after 15 minutes, got %v", got)
+	}
+	if got := deliver(reminder, entries, at(18, 0), time.UTC); len(got) != 0 {
+		t.Fatalf("Expected no reminder outside working hours, got %v", got)
+	}
+
+	running := append(entries, storage.Entry{Start: at(10, 40), Text: "Review"})
+	if got := deliver(reminder, running, at(11, 0), time.UTC); len(got) != 0 {
+		t.Fatalf("Expected no reminder while an entry runs, got %v", got)
+	}
+}
+
+func TestReminderLongRunningAndGoal(t *testing.T) {
+	reminder := NewReminder(Rules{LongRunning: 2 * time.Hour, DailyGoal: 4 * time.Hour})
+	entries := []storage.Entry{
+		finished(at(8, 0), at(9, 30), "Email"),
+		{Start: at(10, 0), Text: "Deep work #project"},
+	}
+
+	if got := deliver(reminder, entries, at(11, 0), time.UTC); len(got) != 0 {
+		t.Fatalf("Expected no reminders yet, got %v", got)
+	}
+	got := deliver(reminder, entries, at(12, 0), time.UTC)
+	if len(got) != 1 || got[0].Title != "Still running: Deep work #project" {
+		t.Fatalf("Expected a long running reminder, got %v", got)
+	}
+	got = deliver(reminder, entries, at(12, 30), time.UTC)
+	if len(got) != 1 || got[0].Title != "Daily goal reached" {
+		t.Fatalf("Expected only the goal reminder, got %v", got)
+	}
+	if got := deliver(reminder, entries, at(13, 0), time.UTC); len(got) != 0 {
+		t.Fatalf("Expected no repeated reminders, got %v", got)
+	}
+}
+
+func TestReminderDailySummary(t *testing.T) {
+	reminder := NewReminder(Rules{Summary: true, SummaryAt: 17 * time.Hour})
+	entries := []storage.Entry{
+		finished(at(9, 0), at(11, 0), "API #clientA/api"),
+		finished(at(11, 0), at(12, 0), "Web #clientA/web #design"),
+		finished(at(13, 0), at(13, 30), "Lunch walk"),
+	}
+
+	if got := deliver(reminder, entries, at(16, 59), time.UTC); len(got) != 0 {
+		t.Fatalf("Expected no summary before 17:00, got %v", got)
+	}
+	got := deliver(reminder, entries, at(17, 0), time.UTC)
+	if len(got) != 1 {
+		t.Fatalf("Expected a summary at 17:00, got %v", got)
+	}
+	if got[0].Title != "Today: 3h30m" {
+		t.Errorf("Title mismatch: got %q", got[0].Title)
+	}
+	if want := "#clientA 3h00m, #design 1h00m, (untagged) 0h30m"; got[0].Body != want {
+		t.Errorf("Body mismatch: got %q, want %q", got[0].Body, want)
+	}
+	if got := deliver(reminder, entries, at(18, 0), time.UTC); len(got) != 0 {
+		t.Fatalf("Expected one summary per day, got %v", got)
+	}
+}
+
+func TestReminderRepeatsUntilSent(t *testing.T) {
+	reminder := NewReminder(Rules{LongRunning: time.Hour, DailyGoal: time.Hour})
+	entries := []storage.Entry{{Start: at(9, 0), Text: "Deep work"}}
+
+	if got := reminder.Check(entries, at(10, 0), time.UTC); len(got) != 2 {
+		t.Fatalf("Expected two reminders, got %v", got)
+	}
+	got := reminder.Check(entries, at(10, 1), time.UTC)
+	if len(got) != 2 {
+		t.Fatalf("Expected undelivered reminders to be due again, got %v", got)
+	}
+	reminder.Sent(got[1])
+	got = reminder.Check(entries, at(10, 2), time.UTC)
+	if len(got) != 1 || got[0].Title != "Still running: Deep work" {
+		t.Fatalf("Expected only the undelivered long running reminder, got %v", got)
+	}
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Attribute is a key-value pair parsed from a `key:value` or `key=value`
+// word in the entry text, such as `ticket:ABC-123` or `client=acme`.
+type Attribute struct {
+	Key   string // Lowercased key
+	Value string
+}
+
+// attributePattern matches words like "ticket:ABC-123" or "client=acme".
+// Keys must start with a letter, which keeps times like "10:30" out.
+var attributePattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_-]*)[:=](\S+)$`)
+
+// ParseAttribute parses a single word as an attribute.
+// Returns false for tags, time overrides and URL-like values.
+func ParseAttribute(word string) (Attribute, bool) {
+	if strings.HasPrefix(word, "#") || strings.HasPrefix(word, "@") {
+		return Attribute{}, false
+	}
+	matches := attributePattern.FindStringSubmatch(word)
+	if matches == nil {
+		return Attribute{}, false
+	}
+	// Skip URLs such as https://example.com
+	if strings.HasPrefix(matches[2], "/") {
+		return Attribute{}, false
+	}
+	return Attribute{Key: strings.ToLower(matches[1]), Value: matches[2]}, true
+}
+
+// String formats the attribute as it appears in entry text.
+func (a Attribute) String() string {
+	return a.Key + ":" + a.Value
+}
+
+// Attributes extracts all key:value attributes from the entry text,
+// in the order they appear. Returns an empty slice if none are found.
+func (e Entry) Attributes() []Attribute {
+	var attrs []Attribute
+	for _, word := range strings.Fields(e.Text) {
+		if attr, ok := ParseAttribute(word); ok {
+			attrs = append(attrs, attr)
+		}
+	}
+	return attrs
+}
+
+// Attribute returns the value of the first attribute with the given key.
+// The key is matched case-insensitively.
+func (e Entry) Attribute(key string) (string, bool) {
+	key = strings.ToLower(key)
+	for _, attr := range e.Attributes() {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
+
+// MatchesAttribute reports whether the entry has the attribute key and,
+// if value is non-empty, whether one of its values equals value
+// (case-insensitively).
+func (e Entry) MatchesAttribute(key, value string) bool {
+	key = strings.ToLower(key)
+	for _, attr := range e.Attributes() {
+		if attr.Key != key {
+			continue
+		}
+		if value == "" || strings.EqualFold(attr.Value, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAttributeFilter parses a filter of the form "key", "key:value" or
+// "key=value". An empty value matches any entry that has the key.
+func ParseAttributeFilter(filter string) Attribute {
+	if idx := strings.IndexAny(filter, ":="); idx != -1 {
+		return Attribute{Key: strings.ToLower(filter[:idx]), Value: filter[idx+1:]}
+	}
+	return Attribute{Key: strings.ToLower(filter)}
+}
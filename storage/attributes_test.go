@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEntryAttributes(t *testing.T) {
+	entry := Entry{
+		Text: "Fix login ticket:ABC-123 Client=acme #backend at 10:30 see https://example.com",
+	}
+	attrs := entry.Attributes()
+	if len(attrs) != 2 {
+		t.Fatalf("Expected 2 attributes, got %v", attrs)
+	}
+	if attrs[0].Key != "ticket" || attrs[0].Value != "ABC-123" {
+		t.Errorf("Expected ticket:ABC-123, got %v", attrs[0])
+	}
+	if attrs[1].Key != "client" || attrs[1].Value != "acme" {
+		t.Errorf("Expected client:acme, got %v", attrs[1])
+	}
+
+	value, ok := entry.Attribute("TICKET")
+	if !ok || value != "ABC-123" {
+		t.Errorf("Expected ticket lookup to return ABC-123, got %q (%v)", value, ok)
+	}
+	if _, ok := entry.Attribute("missing"); ok {
+		t.Error("Expected missing attribute lookup to fail")
+	}
+
+	if !entry.MatchesAttribute("client", "ACME") {
+		t.Error("Expected client filter to match case-insensitively")
+	}
+	if !entry.MatchesAttribute("ticket", "") {
+		t.Error("Expected key-only filter to match")
+	}
+	if entry.MatchesAttribute("ticket", "XYZ-1") {
+		t.Error("Expected ticket filter with other value not to match")
+	}
+}
+
+func TestAttributesRoundTrip(t *testing.T) {
+	end := time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC)
+	entry := Entry{
+		Start: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		End:   &end,
+		Text:  "Review ticket:ABC-123 client=acme #review",
+	}
+
+	parsed, err := ParseEntry(FormatEntry(entry))
+	if err != nil {
+		t.Fatalf("Failed to parse entry: %v", err)
+	}
+	attrs := parsed.Attributes()
+	if len(attrs) != 2 || attrs[0].String() != "ticket:ABC-123" || attrs[1].String() != "client:acme" {
+		t.Errorf("Expected attributes to survive round trip, got %v", attrs)
+	}
+}
+
+func TestParseAttributeFilter(t *testing.T) {
+	filter := ParseAttributeFilter("Ticket=ABC-1")
+	if filter.Key != "ticket" || filter.Value != "ABC-1" {
+		t.Errorf("Expected ticket=ABC-1, got %v", filter)
+	}
+	filter = ParseAttributeFilter("client")
+	if filter.Key != "client" || filter.Value != "" {
+		t.Errorf("Expected key-only filter, got %v", filter)
+	}
+}
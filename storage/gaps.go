@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WorkingHours is the daily window in which untracked time counts as a gap.
+type WorkingHours struct {
+	Start time.Duration // Offset from local midnight
+	End   time.Duration // Offset from local midnight
+	Days  [7]bool       // Indexed by time.Weekday
+}
+
+// DefaultWorkingHours is 09:00-17:00, Monday to Friday.
+var DefaultWorkingHours = WorkingHours{
+	Start: 9 * time.Hour,
+	End:   17 * time.Hour,
+	Days:  [7]bool{time.Monday: true, time.Tuesday: true, time.Wednesday: true, time.Thursday: true, time.Friday: true},
+}
+
+// ParseWorkingHours parses a window like "09:00-17:00" and a list of
+// weekday names ("mon", "tuesday", ...). Empty values keep the defaults.
+func ParseWorkingHours(window string, days []string) (WorkingHours, error) {
+	hours := DefaultWorkingHours
+
+	if window != "" {
+		startText, endText, ok := strings.Cut(window, "-")
+		if !ok {
+			return WorkingHours{}, fmt.Errorf("invalid working hours %q (use HH:MM-HH:MM)", window)
+		}
+		startHour, startMinute, err := ParseTimeOfDay(strings.TrimSpace(startText))
+		if err != nil {
+			return WorkingHours{}, fmt.Errorf("invalid working hours %q: %w", window, err)
+		}
+		endHour, endMinute, err := ParseTimeOfDay(strings.TrimSpace(endText))
+		if err != nil {
+			return WorkingHours{}, fmt.Errorf("invalid working hours %q: %w", window, err)
+		}
+		hours.Start = time.Duration(startHour)*time.Hour + time.Duration(startMinute)*time.Minute
+		hours.End = time.Duration(endHour)*time.Hour + time.Duration(endMinute)*time.Minute
+		if hours.End <= hours.Start {
+			return WorkingHours{}, fmt.Errorf("invalid working hours %q: end must be after start", window)
+		}
+	}
+
+	if len(days) > 0 {
+		hours.Days = [7]bool{}
+		for _, day := range days {
+			weekday, err := ParseWeekday(day)
+			if err != nil {
+				return WorkingHours{}, err
+			}
+			hours.Days[weekday] = true
+		}
+	}
+	return hours, nil
+}
+
+// ParseWeekday parses a weekday name or its three-letter abbreviation.
+func ParseWeekday(value string) (time.Weekday, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if value == name || value == name[:3] {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid weekday: %s", value)
+}
+
+// Gap is an untracked interval within working hours.
+type Gap struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration returns the length of the gap.
+func (g Gap) Duration() time.Duration {
+	return g.End.Sub(g.Start)
+}
+
+// FindGaps returns the intervals within working hours in [start, end) that
+// no entry covers, in chronological order. Working hours are evaluated in
+// tz, time after now is never a gap, and gaps shorter than minGap are
+// dropped. Open entries run until now.
+func FindGaps(entries []Entry, start, end, now time.Time, hours WorkingHours, tz *time.Location, minGap time.Duration) []Gap {
+	if now.Before(end) {
+		end = now
+	}
+
+	// Entry intervals sorted by start
+	type interval struct{ start, end time.Time }
+	var covered []interval
+	for _, entry := range entries {
+		entryEnd := now
+		if entry.End != nil {
+			entryEnd = *entry.End
+		}
+		if entryEnd.After(entry.Start) {
+			covered = append(covered, interval{entry.Start, entryEnd})
+		}
+	}
+	sort.Slice(covered, func(i, j int) bool {
+		return covered[i].start.Before(covered[j].start)
+	})
+
+	var gaps []Gap
+	startLocal := start.In(tz)
+	day := time.Date(startLocal.Year(), startLocal.Month(), startLocal.Day(), 0, 0, 0, 0, tz)
+	for ; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if !hours.Days[day.Weekday()] {
+			continue
+		}
+		windowStart := day.Add(hours.Start)
+		windowEnd := day.Add(hours.End)
+		if windowStart.Before(start) {
+			windowStart = start
+		}
+		if windowEnd.After(end) {
+			windowEnd = end
+		}
+
+		// Walk the window, skipping over covered intervals
+		cursor := windowStart
+		for _, c := range covered {
+			if !cursor.Before(windowEnd) {
+				break
+			}
+			if !c.end.After(cursor) || !c.start.Before(windowEnd) {
+				continue
+			}
+			if c.start.After(cursor) {
+				gaps = appendGap(gaps, cursor, c.start, minGap)
+			}
+			if c.end.After(cursor) {
+				cursor = c.end
+			}
+		}
+		if cursor.Before(windowEnd) {
+			gaps = appendGap(gaps, cursor, windowEnd, minGap)
+		}
+	}
+	return gaps
+}
+
+// appendGap appends [start, end) to gaps if it is at least minGap long.
+func appendGap(gaps []Gap, start, end time.Time, minGap time.Duration) []Gap {
+	if end.Sub(start) <= 0 || end.Sub(start) < minGap {
+		return gaps
+	}
+	return append(gaps, Gap{Start: start, End: end})
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindGaps(t *testing.T) {
+	// Monday 2024-01-01
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+	end1 := at(1, 10, 0)
+	end2 := at(1, 12, 0)
+	end3 := at(1, 11, 0)
+	end4 := at(1, 16, 58)
+	entries := []Entry{
+		{Start: at(1, 8, 0), End: &end1, Text: "Early start"},
+		{Start: at(1, 10, 30), End: &end2, Text: "Late morning"},
+		{Start: at(1, 10, 45), End: &end3, Text: "Overlapping"},
+		{Start: at(1, 13, 0), End: &end4, Text: "Afternoon"},
+	}
+
+	// Monday to Sunday; the weekend is outside working hours
+	gaps := FindGaps(entries, at(1, 0, 0), at(8, 0, 0), at(8, 0, 0), DefaultWorkingHours, time.UTC, 5*time.Minute)
+
+	expected := []Gap{
+		{Start: at(1, 10, 0), End: at(1, 10, 30)},
+		{Start: at(1, 12, 0), End: at(1, 13, 0)},
+		// 16:58-17:00 is shorter than minGap
+		{Start: at(2, 9, 0), End: at(2, 17, 0)},
+		{Start: at(3, 9, 0), End: at(3, 17, 0)},
+		{Start: at(4, 9, 0), End: at(4, 17, 0)},
+		{Start: at(5, 9, 0), End: at(5, 17, 0)},
+	}
+	if len(gaps) != len(expected) {
+		t.Fatalf("Expected %d gaps, got %d: %v", len(expected), len(gaps), gaps)
+	}
+	for i, gap := range gaps {
+		if !gap.Start.Equal(expected[i].Start) || !gap.End.Equal(expected[i].End) {
+			t.Errorf("Gap %d: expected %v-%v, got %v-%v", i, expected[i].Start, expected[i].End, gap.Start, gap.End)
+		}
+	}
+}
+
+func TestFindGapsStopsAtNow(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC)
+	running := []Entry{{Start: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), Text: "Running"}}
+
+	gaps := FindGaps(running, start, start.AddDate(0, 0, 1), now, DefaultWorkingHours, time.UTC, 0)
+	if len(gaps) != 1 || gaps[0].Duration() != time.Hour {
+		t.Fatalf("Expected a single 1h gap before the running entry, got %v", gaps)
+	}
+}
+
+func TestParseWorkingHours(t *testing.T) {
+	hours, err := ParseWorkingHours("08:30-16:00", []string{"mon", "Tuesday"})
+	if err != nil {
+		t.Fatalf("Failed to parse working hours: %v", err)
+	}
+	if hours.Start != 8*time.Hour+30*time.Minute || hours.End != 16*time.Hour {
+		t.Errorf("Unexpected window %v-%v", hours.Start, hours.End)
+	}
+	if !hours.Days[time.Monday] || !hours.Days[time.Tuesday] || hours.Days[time.Wednesday] {
+		t.Errorf("Unexpected days %v", hours.Days)
+	}
+
+	for _, window := range []string{"9-17", "17:00-09:00", "09:00"} {
+		if _, err := ParseWorkingHours(window, nil); err == nil {
+			t.Errorf("Expected error for %q", window)
+		}
+	}
+	if _, err := ParseWorkingHours("", []string{"someday"}); err == nil {
+		t.Error("Expected error for invalid weekday")
+	}
+}
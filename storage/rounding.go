@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RoundMode selects how durations are rounded to an increment.
+type RoundMode int
+
+const (
+	RoundNone RoundMode = iota
+	RoundNearest
+	RoundUp
+	RoundDown
+)
+
+// RoundScope selects whether rounding applies to each entry or to totals.
+type RoundScope int
+
+const (
+	RoundPerEntry RoundScope = iota
+	RoundPerAggregate
+)
+
+// Rounding describes a rounding policy for reported durations.
+// The zero value disables rounding.
+type Rounding struct {
+	Mode      RoundMode
+	Increment time.Duration
+	Scope     RoundScope
+}
+
+// Enabled reports whether the policy changes any duration.
+func (r Rounding) Enabled() bool {
+	return r.Mode != RoundNone && r.Increment > 0
+}
+
+// Round rounds d to the policy increment, regardless of scope.
+func (r Rounding) Round(d time.Duration) time.Duration {
+	if !r.Enabled() || d <= 0 {
+		return d
+	}
+	switch r.Mode {
+	case RoundNearest:
+		return d.Round(r.Increment)
+	case RoundUp:
+		rounded := d.Truncate(r.Increment)
+		if rounded < d {
+			rounded += r.Increment
+		}
+		return rounded
+	case RoundDown:
+		return d.Truncate(r.Increment)
+	}
+	return d
+}
+
+// Entry rounds the duration of a single entry if the policy is per-entry.
+func (r Rounding) Entry(d time.Duration) time.Duration {
+	if r.Scope != RoundPerEntry {
+		return d
+	}
+	return r.Round(d)
+}
+
+// Aggregate rounds a summed duration if the policy is per-aggregate.
+func (r Rounding) Aggregate(d time.Duration) time.Duration {
+	if r.Scope != RoundPerAggregate {
+		return d
+	}
+	return r.Round(d)
+}
+
+// String formats the policy in the form accepted by ParseRounding.
+func (r Rounding) String() string {
+	if !r.Enabled() {
+		return "none"
+	}
+	var mode string
+	switch r.Mode {
+	case RoundNearest:
+		mode = "nearest"
+	case RoundUp:
+		mode = "up"
+	case RoundDown:
+		mode = "down"
+	}
+	if r.Increment%time.Hour == 0 {
+		return fmt.Sprintf("%s:%dh", mode, int(r.Increment.Hours()))
+	}
+	return fmt.Sprintf("%s:%dm", mode, int(r.Increment.Minutes()))
+}
+
+// ParseRounding parses a rounding spec such as "none", "up:15m" or "nearest:6m".
+// The scope is left at its default (per-entry).
+func ParseRounding(value string) (Rounding, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" || value == "none" {
+		return Rounding{}, nil
+	}
+
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 {
+		return Rounding{}, fmt.Errorf("rounding must be 'none' or MODE:INCREMENT (e.g. up:15m)")
+	}
+
+	var mode RoundMode
+	switch parts[0] {
+	case "nearest":
+		mode = RoundNearest
+	case "up":
+		mode = RoundUp
+	case "down":
+		mode = RoundDown
+	case "none":
+		return Rounding{}, nil
+	default:
+		return Rounding{}, fmt.Errorf("invalid rounding mode: %s", parts[0])
+	}
+
+	increment, err := time.ParseDuration(parts[1])
+	if err != nil {
+		return Rounding{}, fmt.Errorf("invalid rounding increment: %s", parts[1])
+	}
+	if increment < time.Minute {
+		return Rounding{}, fmt.Errorf("rounding increment must be at least 1m")
+	}
+
+	return Rounding{Mode: mode, Increment: increment}, nil
+}
+
+// ParseRoundScope parses "entry" or "aggregate".
+func ParseRoundScope(value string) (RoundScope, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "entry":
+		return RoundPerEntry, nil
+	case "aggregate", "total":
+		return RoundPerAggregate, nil
+	}
+	return RoundPerEntry, fmt.Errorf("invalid rounding scope: %s", value)
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRoundingModes(t *testing.T) {
+	d := 37 * time.Minute
+
+	cases := []struct {
+		spec     string
+		expected time.Duration
+	}{
+		{"none", 37 * time.Minute},
+		{"nearest:15m", 30 * time.Minute},
+		{"up:15m", 45 * time.Minute},
+		{"down:15m", 30 * time.Minute},
+		{"up:6m", 42 * time.Minute},
+		{"nearest:30m", 30 * time.Minute},
+	}
+
+	for _, c := range cases {
+		policy, err := ParseRounding(c.spec)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", c.spec, err)
+		}
+		if got := policy.Round(d); got != c.expected {
+			t.Errorf("%s: expected %v, got %v", c.spec, c.expected, got)
+		}
+	}
+}
+
+func TestRoundingScope(t *testing.T) {
+	policy, err := ParseRounding("up:15m")
+	if err != nil {
+		t.Fatalf("Failed to parse rounding: %v", err)
+	}
+
+	if got := policy.Entry(10 * time.Minute); got != 15*time.Minute {
+		t.Errorf("Expected per-entry rounding to 15m, got %v", got)
+	}
+	if got := policy.Aggregate(10 * time.Minute); got != 10*time.Minute {
+		t.Errorf("Expected aggregate to be untouched for per-entry policy, got %v", got)
+	}
+
+	policy.Scope = RoundPerAggregate
+	if got := policy.Entry(10 * time.Minute); got != 10*time.Minute {
+		t.Errorf("Expected entry to be untouched for per-aggregate policy, got %v", got)
+	}
+	if got := policy.Aggregate(10 * time.Minute); got != 15*time.Minute {
+		t.Errorf("Expected aggregate rounding to 15m, got %v", got)
+	}
+}
+
+func TestParseRoundingErrors(t *testing.T) {
+	for _, spec := range []string{"up", "sideways:15m", "up:abc", "up:10s"} {
+		if _, err := ParseRounding(spec); err == nil {
+			t.Errorf("Expected error for %q", spec)
+		}
+	}
+
+	policy, err := ParseRounding("nearest:6m")
+	if err != nil {
+		t.Fatalf("Failed to parse rounding: %v", err)
+	}
+	if policy.String() != "nearest:6m" {
+		t.Errorf("Expected nearest:6m, got %s", policy.String())
+	}
+}
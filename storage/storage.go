@@ -1,9 +1,12 @@
 package storage
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 )
@@ -43,6 +46,23 @@ func (e Entry) Tags() []string {
 	return tags
 }
 
+// TagPaths returns every normalized tag of the entry together with all of
+// its parent tags, each listed once. For "#clientA/api #clientA/web" it
+// returns ["clientA", "clientA/api", "clientA/web"].
+func (e Entry) TagPaths() []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, tag := range e.Tags() {
+		for _, path := range TagAncestors(tag) {
+			if !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths
+}
+
 // DefaultLogPath returns the log file path from environment variable
 // or defaults to ~/.lazytime/log.txt.
 func DefaultLogPath() string {
@@ -140,16 +160,107 @@ func ParseEntry(raw string) (Entry, error) {
 // ReadEntries reads all entries from the log file.
 // Skips empty lines and lines starting with #.
 func ReadEntries(path string) ([]Entry, error) {
+	path, err := prepareLogPath(path)
+	if err != nil {
+		return nil, err
+	}
+	unlock, err := lockLog(path, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return readEntries(path)
+}
+
+// WriteEntries writes all entries to the log file.
+func WriteEntries(entries []Entry, path string) error {
+	path, err := prepareLogPath(path)
+	if err != nil {
+		return err
+	}
+	unlock, err := lockLog(path, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return writeLines(formatEntries(entries), path)
+}
+
+// AppendEntry appends a single entry to the log file.
+func AppendEntry(entry Entry, path string) error {
+	path, err := prepareLogPath(path)
+	if err != nil {
+		return err
+	}
+	unlock, err := lockLog(path, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return appendLines([]string{FormatEntry(entry)}, path)
+}
+
+// UpdateEntries reads the log file, passes its entries to update and
+// writes back the entries update returns, holding the log lock
+// throughout so that no other process changes the log in between. New
+// entries after the existing ones are appended, keeping the rest of the
+// file as is; other changes rewrite the file. Nothing is written if
+// update fails.
+func UpdateEntries(path string, update func(entries []Entry) ([]Entry, error)) error {
+	path, err := prepareLogPath(path)
+	if err != nil {
+		return err
+	}
+	unlock, err := lockLog(path, true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := readEntries(path)
+	if err != nil {
+		return err
+	}
+	// Formatted before update, which may change entries in place
+	before := formatEntries(entries)
+	updated, err := update(entries)
+	if err != nil {
+		return err
+	}
+	after := formatEntries(updated)
+	if len(after) >= len(before) && slices.Equal(after[:len(before)], before) {
+		if len(after) == len(before) {
+			return nil
+		}
+		return appendLines(after[len(before):], path)
+	}
+	return writeLines(after, path)
+}
+
+// LogVersion identifies the state of the log holding entries, for
+// detecting changes made since it was read.
+func LogVersion(entries []Entry) string {
+	hash := sha256.New()
+	for _, line := range formatEntries(entries) {
+		hash.Write([]byte(line + "\n"))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// prepareLogPath resolves an empty path to the default log path and
+// creates the log directory.
+func prepareLogPath(path string) (string, error) {
 	if path == "" {
 		path = DefaultLogPath()
 	}
-
-	// Ensure directory exists
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create log directory: %w", err)
 	}
+	return path, nil
+}
 
+// readEntries reads the log file; the caller holds the log lock.
+func readEntries(path string) ([]Entry, error) {
 	// Read file if it exists
 	content, err := os.ReadFile(path)
 	if err != nil {
@@ -178,49 +289,35 @@ func ReadEntries(path string) ([]Entry, error) {
 	return entries, nil
 }
 
-// WriteEntries writes all entries to the log file.
-func WriteEntries(entries []Entry, path string) error {
-	if path == "" {
-		path = DefaultLogPath()
-	}
-
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create log directory: %w", err)
-	}
-
-	var lines []string
-	for _, entry := range entries {
-		lines = append(lines, FormatEntry(entry))
+// formatEntries formats entries as log lines.
+func formatEntries(entries []Entry) []string {
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		lines[i] = FormatEntry(entry)
 	}
+	return lines
+}
 
+// writeLines replaces the log file with lines; the caller holds the log
+// lock.
+func writeLines(lines []string, path string) error {
 	content := strings.Join(lines, "\n")
 	if len(lines) > 0 {
 		content += "\n"
 	}
-
 	return os.WriteFile(path, []byte(content), 0644)
 }
 
-// AppendEntry appends a single entry to the log file.
-func AppendEntry(entry Entry, path string) error {
-	if path == "" {
-		path = DefaultLogPath()
-	}
-
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create log directory: %w", err)
-	}
-
-	line := FormatEntry(entry) + "\n"
+// appendLines appends lines to the log file; the caller holds the log
+// lock.
+func appendLines(lines []string, path string) error {
 	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		return fmt.Errorf("failed to open log file: %w", err)
 	}
 	defer file.Close()
 
-	_, err = file.WriteString(line)
+	_, err = file.WriteString(strings.Join(lines, "\n") + "\n")
 	return err
 }
 
@@ -273,3 +370,59 @@ func CheckOverlap(entries []Entry, candidate Entry, now time.Time) (Entry, time.
 	return Entry{}, 0, false
 }
 
+// FindByStart returns the index of the entry starting at the given time.
+// Entries cannot overlap, so the start time identifies an entry.
+// Returns -1 if no entry starts at that time.
+func FindByStart(entries []Entry, start time.Time) int {
+	for i, entry := range entries {
+		if entry.Start.Equal(start) {
+			return i
+		}
+	}
+	return -1
+}
+
+// SplitEntry splits an entry at the given time into two consecutive entries
+// with the same text. The second entry stays open if the original was open.
+func SplitEntry(entry Entry, at time.Time) (Entry, Entry, error) {
+	if !at.After(entry.Start) {
+		return Entry{}, Entry{}, fmt.Errorf("split time must be after the start time")
+	}
+	if entry.End != nil && !at.Before(*entry.End) {
+		return Entry{}, Entry{}, fmt.Errorf("split time must be before the end time")
+	}
+
+	splitAt := at
+	first := Entry{Start: entry.Start, End: &splitAt, Text: entry.Text}
+	second := Entry{Start: at, End: entry.End, Text: entry.Text}
+	return first, second, nil
+}
+
+// SplitIdle cuts the idle period from start to end out of an entry. The
+// entry is split around the period, which becomes an entry with text, or
+// is dropped if text is empty. The part after the period stays open if the
+// original was open; the part before it is left out when the period starts
+// with the entry.
+func SplitIdle(entry Entry, start, end time.Time, text string) ([]Entry, error) {
+	if start.Before(entry.Start) {
+		return nil, fmt.Errorf("idle period must start after the entry's start time")
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("idle period must end after it starts")
+	}
+	if entry.End != nil && !end.Before(*entry.End) {
+		return nil, fmt.Errorf("idle period must end before the entry's end time")
+	}
+
+	var parts []Entry
+	if start.After(entry.Start) {
+		idleStart := start
+		parts = append(parts, Entry{Start: entry.Start, End: &idleStart, Text: entry.Text})
+	}
+	if text != "" {
+		idleEnd := end
+		parts = append(parts, Entry{Start: start, End: &idleEnd, Text: text})
+	}
+	parts = append(parts, Entry{Start: end, End: entry.End, Text: entry.Text})
+	return parts, nil
+}
@@ -1,6 +1,11 @@
 package storage
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -157,3 +162,196 @@ func TestFindOpen(t *testing.T) {
 	}
 }
 
+func TestSplitEntry(t *testing.T) {
+	end := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	entry := Entry{
+		Start: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		End:   &end,
+		Text:  "Long task #project",
+	}
+
+	at := time.Date(2024, 1, 1, 11, 15, 0, 0, time.UTC)
+	first, second, err := SplitEntry(entry, at)
+	if err != nil {
+		t.Fatalf("Failed to split entry: %v", err)
+	}
+	if !first.Start.Equal(entry.Start) || first.End == nil || !first.End.Equal(at) {
+		t.Errorf("Unexpected first part: %v - %v", first.Start, first.End)
+	}
+	if !second.Start.Equal(at) || second.End == nil || !second.End.Equal(end) {
+		t.Errorf("Unexpected second part: %v - %v", second.Start, second.End)
+	}
+	if first.Text != entry.Text || second.Text != entry.Text {
+		t.Errorf("Expected both parts to keep the text %q", entry.Text)
+	}
+
+	if _, _, err := SplitEntry(entry, end); err == nil {
+		t.Error("Expected error when splitting at the end time")
+	}
+	if _, _, err := SplitEntry(entry, entry.Start); err == nil {
+		t.Error("Expected error when splitting at the start time")
+	}
+
+	open := Entry{Start: entry.Start, Text: "Open"}
+	_, second, err = SplitEntry(open, at)
+	if err != nil {
+		t.Fatalf("Failed to split open entry: %v", err)
+	}
+	if second.End != nil {
+		t.Error("Expected second part of an open entry to stay open")
+	}
+}
+
+func TestSplitIdle(t *testing.T) {
+	entry := Entry{Start: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), Text: "Deep work #project"}
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 1, 11, 30, 0, 0, time.UTC)
+
+	// Discarding leaves a gap between the two parts
+	parts, err := SplitIdle(entry, start, end, "")
+	if err != nil {
+		t.Fatalf("Failed to discard idle time: %v", err)
+	}
+	if len(parts) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(parts))
+	}
+	if parts[0].End == nil || !parts[0].End.Equal(start) || parts[0].Text != entry.Text {
+		t.Errorf("Unexpected first part: %v - %v %q", parts[0].Start, parts[0].End, parts[0].Text)
+	}
+	if !parts[1].Start.Equal(end) || parts[1].End != nil || parts[1].Text != entry.Text {
+		t.Errorf("Expected the second part to run from the end of the idle time, got %v - %v %q", parts[1].Start, parts[1].End, parts[1].Text)
+	}
+
+	// Reassigning logs the idle period with the new text
+	parts, err = SplitIdle(entry, start, end, "Lunch")
+	if err != nil {
+		t.Fatalf("Failed to reassign idle time: %v", err)
+	}
+	if len(parts) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(parts))
+	}
+	if !parts[1].Start.Equal(start) || parts[1].End == nil || !parts[1].End.Equal(end) || parts[1].Text != "Lunch" {
+		t.Errorf("Unexpected reassigned part: %v - %v %q", parts[1].Start, parts[1].End, parts[1].Text)
+	}
+
+	// Idle from the start drops the empty first part
+	parts, err = SplitIdle(entry, entry.Start, end, "")
+	if err != nil {
+		t.Fatalf("Failed to discard idle time from the start: %v", err)
+	}
+	if len(parts) != 1 || !parts[0].Start.Equal(end) {
+		t.Errorf("Expected only the part after the idle time, got %v", parts)
+	}
+
+	closedEnd := time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC)
+	closed := Entry{Start: entry.Start, End: &closedEnd, Text: "Closed"}
+	if _, err := SplitIdle(closed, start, end, ""); err == nil {
+		t.Error("Expected error for idle time past the entry's end")
+	}
+	if _, err := SplitIdle(entry, entry.Start.Add(-time.Minute), end, ""); err == nil {
+		t.Error("Expected error for idle time before the entry's start")
+	}
+	if _, err := SplitIdle(entry, end, start, ""); err == nil {
+		t.Error("Expected error for an empty idle period")
+	}
+}
+
+func TestFindByStart(t *testing.T) {
+	entries := []Entry{
+		{Start: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), Text: "First"},
+		{Start: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), Text: "Second"},
+	}
+	if idx := FindByStart(entries, time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)); idx != 1 {
+		t.Errorf("Expected index 1, got %d", idx)
+	}
+	if idx := FindByStart(entries, time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC)); idx != -1 {
+		t.Errorf("Expected -1 for unknown start, got %d", idx)
+	}
+}
+
+func TestUpdateEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.txt")
+	first := Entry{Start: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), Text: "First"}
+	if err := os.WriteFile(path, []byte("# Work log\n"+FormatEntry(first)+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Adding an entry appends it, keeping the comment
+	second := Entry{Start: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), Text: "Second"}
+	err := UpdateEntries(path, func(entries []Entry) ([]Entry, error) {
+		return append(entries, second), nil
+	})
+	if err != nil {
+		t.Fatalf("UpdateEntries failed: %v", err)
+	}
+	content, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(content), "# Work log\n") {
+		t.Errorf("Expected the comment to be kept, got:\n%s", content)
+	}
+	entries, err := ReadEntries(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[1].Text != "Second" {
+		t.Errorf("Expected an appended entry, got %v", entries)
+	}
+
+	// Changes to existing entries rewrite the file, even when made in place
+	err = UpdateEntries(path, func(entries []Entry) ([]Entry, error) {
+		entries[0].Text = "First edited"
+		return entries[:1], nil
+	})
+	if err != nil {
+		t.Fatalf("UpdateEntries failed: %v", err)
+	}
+	if entries, _ := ReadEntries(path); len(entries) != 1 || entries[0].Text != "First edited" {
+		t.Errorf("Expected only the edited first entry, got %v", entries)
+	}
+
+	// A failed update writes nothing
+	failed := errors.New("rejected")
+	err = UpdateEntries(path, func(entries []Entry) ([]Entry, error) {
+		return nil, failed
+	})
+	if !errors.Is(err, failed) {
+		t.Errorf("Expected the update error, got %v", err)
+	}
+	if entries, _ := ReadEntries(path); len(entries) != 1 {
+		t.Errorf("Expected the log to be unchanged, got %v", entries)
+	}
+}
+
+func TestUpdateEntriesSerializesWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.txt")
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := UpdateEntries(path, func(entries []Entry) ([]Entry, error) {
+				// Each writer starts where the last one left off
+				entryStart := start.Add(time.Duration(len(entries)) * time.Hour)
+				return append(entries, Entry{Start: entryStart, Text: "Work"}), nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	entries, err := ReadEntries(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 20 {
+		t.Fatalf("Expected 20 entries, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		if want := start.Add(time.Duration(i) * time.Hour); !entry.Start.Equal(want) {
+			t.Errorf("Entry %d starts at %v, want %v", i, entry.Start, want)
+		}
+	}
+}
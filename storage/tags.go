@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"sort"
+	"strings"
+)
+
+// TagSeparator joins the levels of a hierarchical tag.
+// ":" is accepted as an alternative separator when parsing.
+const TagSeparator = "/"
+
+// SplitTag splits a hierarchical tag like "clientA/api" or "clientA:api"
+// into its levels, dropping empty levels.
+func SplitTag(tag string) []string {
+	parts := strings.FieldsFunc(tag, func(r rune) bool {
+		return r == '/' || r == ':'
+	})
+	return parts
+}
+
+// NormalizeTag rewrites a hierarchical tag to use TagSeparator between levels.
+func NormalizeTag(tag string) string {
+	return strings.Join(SplitTag(tag), TagSeparator)
+}
+
+// TagKey returns the key tags are matched by: the normalized tag,
+// lowercased. "ClientA:API" and "clienta/api" share the key "clienta/api".
+func TagKey(tag string) string {
+	return strings.ToLower(NormalizeTag(tag))
+}
+
+// TagAncestors returns the normalized tag and all of its parents,
+// ordered from the root to the tag itself.
+// For "clientA:api" it returns ["clientA", "clientA/api"].
+func TagAncestors(tag string) []string {
+	parts := SplitTag(tag)
+	ancestors := make([]string, 0, len(parts))
+	for i := range parts {
+		ancestors = append(ancestors, strings.Join(parts[:i+1], TagSeparator))
+	}
+	return ancestors
+}
+
+// TagParent returns the parent of a hierarchical tag, or "" for a root tag.
+func TagParent(tag string) string {
+	parts := SplitTag(tag)
+	if len(parts) <= 1 {
+		return ""
+	}
+	return strings.Join(parts[:len(parts)-1], TagSeparator)
+}
+
+// TagDepth returns the nesting level of a tag (0 for a root tag).
+func TagDepth(tag string) int {
+	depth := len(SplitTag(tag)) - 1
+	if depth < 0 {
+		return 0
+	}
+	return depth
+}
+
+// TagName returns the last level of a hierarchical tag.
+func TagName(tag string) string {
+	parts := SplitTag(tag)
+	if len(parts) == 0 {
+		return tag
+	}
+	return parts[len(parts)-1]
+}
+
+// IsTagWithin reports whether tag equals parent or is nested below it.
+// The comparison is case-insensitive.
+func IsTagWithin(tag, parent string) bool {
+	tag = TagKey(tag)
+	parent = TagKey(parent)
+	return tag == parent || strings.HasPrefix(tag, parent+TagSeparator)
+}
+
+// HasTagWithin reports whether the entry has parent or one of its child
+// tags. The comparison is case-insensitive.
+func (e Entry) HasTagWithin(parent string) bool {
+	for _, tag := range e.Tags() {
+		if IsTagWithin(tag, parent) {
+			return true
+		}
+	}
+	return false
+}
+
+// CompareTags orders hierarchical tags level by level, case-insensitively,
+// so that children sort directly after their parent.
+func CompareTags(a, b string) int {
+	partsA := SplitTag(strings.ToLower(a))
+	partsB := SplitTag(strings.ToLower(b))
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		if c := strings.Compare(partsA[i], partsB[i]); c != 0 {
+			return c
+		}
+	}
+	return len(partsA) - len(partsB)
+}
+
+// ReplaceTagPrefix renames tag if it equals from or is nested below it,
+// keeping any child levels: ("review/api", "review", "code-review")
+// returns "code-review/api". Returns false if tag is not within from.
+func ReplaceTagPrefix(tag, from, to string) (string, bool) {
+	if !IsTagWithin(tag, from) {
+		return tag, false
+	}
+	parts := SplitTag(tag)
+	rest := parts[len(SplitTag(from)):]
+	return strings.Join(append(SplitTag(to), rest...), TagSeparator), true
+}
+
+// RewriteTags applies rewrite to every #tag word in text. If any tag
+// changes, repeated tags (compared case-insensitively) are dropped so that
+// merging #review into #code-review does not leave "#code-review #code-review".
+// Text without changed tags is returned as is.
+func RewriteTags(text string, rewrite func(tag string) string) string {
+	words := strings.Fields(text)
+	changed := false
+	for i, word := range words {
+		if !strings.HasPrefix(word, "#") || len(word) <= 1 {
+			continue
+		}
+		if newTag := rewrite(word[1:]); newTag != word[1:] {
+			words[i] = "#" + newTag
+			changed = true
+		}
+	}
+	if !changed {
+		return text
+	}
+
+	var result []string
+	seen := make(map[string]bool)
+	for _, word := range words {
+		if strings.HasPrefix(word, "#") && len(word) > 1 {
+			key := TagKey(word[1:])
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		result = append(result, word)
+	}
+	return strings.Join(result, " ")
+}
+
+// ResolveTagAlias maps a tag through an alias table (alias -> canonical tag).
+// Aliases match case-insensitively and also apply to child tags; the most
+// specific alias wins.
+func ResolveTagAlias(tag string, aliases map[string]string) string {
+	ancestors := TagAncestors(tag)
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		for alias, canonical := range aliases {
+			if strings.EqualFold(NormalizeTag(alias), ancestors[i]) {
+				renamed, _ := ReplaceTagPrefix(tag, ancestors[i], canonical)
+				return renamed
+			}
+		}
+	}
+	return tag
+}
+
+// ApplyTagAliases returns a copy of entries with aliased tags replaced by
+// their canonical tag. The original slice is not modified.
+func ApplyTagAliases(entries []Entry, aliases map[string]string) []Entry {
+	if len(aliases) == 0 {
+		return entries
+	}
+	resolved := make([]Entry, len(entries))
+	for i, entry := range entries {
+		entry.Text = RewriteTags(entry.Text, func(tag string) string {
+			return ResolveTagAlias(tag, aliases)
+		})
+		resolved[i] = entry
+	}
+	return resolved
+}
+
+// UniqueTags returns the tags used by the entries as their TagKey, sorted.
+func UniqueTags(entries []Entry) []string {
+	tagSet := make(map[string]bool)
+	for _, entry := range entries {
+		tags := entry.Tags()
+		for _, tag := range tags {
+			tagSet[TagKey(tag)] = true
+		}
+	}
+
+	var tags []string
+	for tag := range tagSet {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestTagAncestors(t *testing.T) {
+	ancestors := TagAncestors("clientA:api/v2")
+	expected := []string{"clientA", "clientA/api", "clientA/api/v2"}
+	if len(ancestors) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, ancestors)
+	}
+	for i := range expected {
+		if ancestors[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, ancestors)
+		}
+	}
+
+	if TagParent("clientA/api") != "clientA" {
+		t.Errorf("Expected parent clientA, got %q", TagParent("clientA/api"))
+	}
+	if TagParent("clientA") != "" {
+		t.Errorf("Expected no parent for root tag, got %q", TagParent("clientA"))
+	}
+	if TagName("clientA/api") != "api" {
+		t.Errorf("Expected name api, got %q", TagName("clientA/api"))
+	}
+}
+
+func TestEntryTagPaths(t *testing.T) {
+	entry := Entry{Text: "Sync #clientA/api #clientA:web #ops"}
+	paths := entry.TagPaths()
+	expected := []string{"clientA", "clientA/api", "clientA/web", "ops"}
+	if len(paths) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, paths)
+	}
+	for i := range expected {
+		if paths[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, paths)
+		}
+	}
+}
+
+func TestIsTagWithin(t *testing.T) {
+	if !IsTagWithin("clientA/api", "clienta") {
+		t.Error("Expected clientA/api to be within clienta")
+	}
+	if !IsTagWithin("clientA", "clientA") {
+		t.Error("Expected a tag to be within itself")
+	}
+	if IsTagWithin("clientAB", "clientA") {
+		t.Error("Expected clientAB not to be within clientA")
+	}
+
+	entry := Entry{Text: "Design review #clientA/web #meeting"}
+	if !entry.HasTagWithin("CLIENTA") || !entry.HasTagWithin("meeting") {
+		t.Error("Expected entry to match its tags and their parents")
+	}
+	if entry.HasTagWithin("clientA/api") {
+		t.Error("Expected entry not to match a sibling tag")
+	}
+}
+
+func TestCompareTags(t *testing.T) {
+	tags := []string{"clientB", "clientA-x", "clientA/web", "clientA", "clientA/api"}
+	sort.Slice(tags, func(i, j int) bool { return CompareTags(tags[i], tags[j]) < 0 })
+	expected := []string{"clientA", "clientA/api", "clientA/web", "clientA-x", "clientB"}
+	for i := range expected {
+		if tags[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, tags)
+		}
+	}
+}
+
+func TestRewriteTags(t *testing.T) {
+	rename := func(tag string) string {
+		renamed, _ := ReplaceTagPrefix(tag, "review", "code-review")
+		return renamed
+	}
+
+	got := RewriteTags("Review PRs #Review/api #code-review/api #team", rename)
+	if got != "Review PRs #code-review/api #team" {
+		t.Errorf("Unexpected rewrite: %q", got)
+	}
+
+	unchanged := "Plan  day #planning"
+	if got := RewriteTags(unchanged, rename); got != unchanged {
+		t.Errorf("Expected untouched text, got %q", got)
+	}
+}
+
+func TestApplyTagAliases(t *testing.T) {
+	aliases := map[string]string{
+		"codereview": "code-review",
+		"review":     "code-review",
+		"clientA":    "acme",
+	}
+	entries := []Entry{
+		{Text: "PRs #CodeReview"},
+		{Text: "API #clientA/api #review"},
+	}
+
+	resolved := ApplyTagAliases(entries, aliases)
+	if resolved[0].Text != "PRs #code-review" {
+		t.Errorf("Expected alias to resolve, got %q", resolved[0].Text)
+	}
+	if resolved[1].Text != "API #acme/api #code-review" {
+		t.Errorf("Expected child tag alias to resolve, got %q", resolved[1].Text)
+	}
+	if entries[0].Text != "PRs #CodeReview" {
+		t.Errorf("Expected original entries to be untouched, got %q", entries[0].Text)
+	}
+}
@@ -0,0 +1,275 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"lazytime/daemon"
+	"lazytime/storage"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// entryActionMsg reports the result of an action on an existing entry.
+type entryActionMsg struct {
+	message string
+	err     error
+}
+
+type entryNotFoundError struct{}
+
+func (e *entryNotFoundError) Error() string {
+	return "entry no longer exists in the log"
+}
+
+// selectedEntry returns the entry highlighted in the entry list as it is
+// written in the log, so that actions prefilled with its text keep the
+// tags the user typed rather than their aliases.
+func (m Model) selectedEntry() (storage.Entry, bool) {
+	if m.pane != PaneList || m.selected < 0 || m.selected >= len(m.rawEntries) {
+		return storage.Entry{}, false
+	}
+	return m.rawEntries[m.selected], true
+}
+
+// moveSelection moves the selection by delta entries in the current list,
+// skipping day headers, and scrolls to keep it visible.
+func (m *Model) moveSelection(delta int) {
+	rows := m.listRows()
+	var positions []int // row positions of entries
+	current := -1
+	for pos, row := range rows {
+		if row.entryIndex < 0 {
+			continue
+		}
+		if row.entryIndex == m.selected {
+			current = len(positions)
+		}
+		positions = append(positions, pos)
+	}
+	if len(positions) == 0 {
+		m.selected = -1
+		return
+	}
+
+	next := 0
+	if current != -1 {
+		next = current + delta
+	}
+	if next < 0 {
+		next = 0
+	}
+	if next >= len(positions) {
+		next = len(positions) - 1
+	}
+	m.selected = rows[positions[next]].entryIndex
+	m.ensureSelectionVisible(rows, positions[next])
+}
+
+// ensureSelectionVisible adjusts the scroll offset so the row at pos (and
+// its day header, if directly above) is inside the list viewport.
+func (m *Model) ensureSelectionVisible(rows []listRow, pos int) {
+	visibleLines := m.layout().mainHeight - 2
+	if visibleLines < 1 {
+		visibleLines = 1
+	}
+
+	top := pos
+	if top > 0 && rows[top-1].entryIndex < 0 {
+		top-- // Keep the day header in view
+	}
+	if top < m.scrollOffset {
+		m.scrollOffset = top
+	}
+	if pos >= m.scrollOffset+visibleLines {
+		m.scrollOffset = pos - visibleLines + 1
+	}
+}
+
+// clampSelection drops the selection if it no longer points at a listed entry.
+func (m *Model) clampSelection() {
+	for _, row := range m.listRows() {
+		if row.entryIndex >= 0 && row.entryIndex == m.selected {
+			return
+		}
+	}
+	m.selected = -1
+}
+
+// rewriteEntryCmd re-reads the log, locates target by its start time and
+// replaces it with the result of rewrite before writing the log back
+// (through the daemon if it is running).
+func rewriteEntryCmd(target storage.Entry, rewrite func(entries []storage.Entry, idx int) ([]storage.Entry, string, error)) tea.Cmd {
+	return func() tea.Msg {
+		var message string
+		err := daemon.Update(daemon.SocketPath(), func(entries []storage.Entry) ([]storage.Entry, error) {
+			idx := storage.FindByStart(entries, target.Start)
+			if idx == -1 {
+				return nil, &entryNotFoundError{}
+			}
+			updated, msg, err := rewrite(entries, idx)
+			message = msg
+			return updated, err
+		})
+		if err != nil {
+			return entryActionMsg{err: err}
+		}
+		return entryActionMsg{message: message}
+	}
+}
+
+// deleteEntryCmd removes the target entry from the log.
+func deleteEntryCmd(target storage.Entry) tea.Cmd {
+	return rewriteEntryCmd(target, func(entries []storage.Entry, idx int) ([]storage.Entry, string, error) {
+		text := entries[idx].Text
+		updated := append(entries[:idx:idx], entries[idx+1:]...)
+		return updated, "Deleted: " + text, nil
+	})
+}
+
+// updateEntryCmd replaces the target entry with updated, rejecting
+// changes that overlap another entry or leave two entries running.
+func updateEntryCmd(target, updated storage.Entry, tz *time.Location) tea.Cmd {
+	return rewriteEntryCmd(target, func(entries []storage.Entry, idx int) ([]storage.Entry, string, error) {
+		others := append(entries[:idx:idx], entries[idx+1:]...)
+		if updated.End == nil && storage.FindOpen(others) != -1 {
+			return nil, "", &entryAlreadyRunningError{}
+		}
+
+		overlapEntry, overlapDuration, hasOverlap := storage.CheckOverlap(others, updated, storage.UTCNow())
+		if hasOverlap {
+			return nil, "", fmt.Errorf("overlaps %q (%s) by %s",
+				overlapEntry.Text, formatEntryRange(overlapEntry, tz), FormatDurationShort(overlapDuration))
+		}
+
+		entries[idx] = updated
+		return entries, "Updated: " + updated.Text, nil
+	})
+}
+
+// formatEntryRange formats an entry's start and end for messages.
+func formatEntryRange(entry storage.Entry, tz *time.Location) string {
+	end := "now"
+	if entry.End != nil {
+		end = entry.End.In(tz).Format("15:04")
+	}
+	return entry.Start.In(tz).Format("2006-01-02 15:04") + " - " + end
+}
+
+// splitEntryCmd splits the target entry into two at the given time.
+func splitEntryCmd(target storage.Entry, at time.Time) tea.Cmd {
+	return rewriteEntryCmd(target, func(entries []storage.Entry, idx int) ([]storage.Entry, string, error) {
+		first, second, err := storage.SplitEntry(entries[idx], storage.ToUTC(at))
+		if err != nil {
+			return nil, "", err
+		}
+		updated := make([]storage.Entry, 0, len(entries)+1)
+		updated = append(updated, entries[:idx]...)
+		updated = append(updated, first, second)
+		updated = append(updated, entries[idx+1:]...)
+		return updated, fmt.Sprintf("Split at %s: %s", at.Format("15:04"), first.Text), nil
+	})
+}
+
+// resumeEntryCmd starts a new open entry now with the target's text.
+func resumeEntryCmd(target storage.Entry) tea.Cmd {
+	return func() tea.Msg {
+		err := daemon.Update(daemon.SocketPath(), func(entries []storage.Entry) ([]storage.Entry, error) {
+			if storage.FindOpen(entries) != -1 {
+				return nil, &entryAlreadyRunningError{}
+			}
+			return append(entries, storage.Entry{
+				Start: storage.ToUTC(storage.LocalNow()),
+				End:   nil,
+				Text:  target.Text,
+			}), nil
+		})
+		if err != nil {
+			return entryActionMsg{err: err}
+		}
+		return entryActionMsg{message: "Resumed: " + target.Text}
+	}
+}
+
+// parseSplitTime parses an HH:MM split time on the entry's start day,
+// moving to the next day for entries that run past midnight.
+func parseSplitTime(value string, entry storage.Entry, tz *time.Location) (time.Time, error) {
+	hour, minute, err := storage.ParseTimeOfDay(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	startLocal := entry.Start.In(tz)
+	at := time.Date(startLocal.Year(), startLocal.Month(), startLocal.Day(), hour, minute, 0, 0, tz)
+	if !at.After(entry.Start) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at, nil
+}
+
+// Fields of the edit modal.
+const (
+	editFieldText = iota
+	editFieldStart
+	editFieldEnd
+)
+
+// editTimeLayout is the format of the edit modal's start and end fields.
+const editTimeLayout = "2006-01-02 15:04"
+
+// parseEditFields builds the edited entry from the edit modal's fields.
+// Start and end accept "YYYY-MM-DD HH:MM" or "HH:MM"; a start time without a
+// date stays on the target's day, and an end time without a date is on the
+// start's day (or the next day if it would be before the start). An empty
+// end leaves the entry running.
+func parseEditFields(fields []string, target storage.Entry, now time.Time) (storage.Entry, error) {
+	tz := now.Location()
+	text := strings.TrimSpace(fields[editFieldText])
+	if text == "" {
+		return storage.Entry{}, &emptyTextError{}
+	}
+
+	start, err := parseEditTime(strings.TrimSpace(fields[editFieldStart]), target.Start, tz)
+	if err != nil {
+		return storage.Entry{}, fmt.Errorf("start: %w", err)
+	}
+	if start.After(now) {
+		return storage.Entry{}, &invalidTimeError{msg: "start time cannot be in the future"}
+	}
+
+	entry := storage.Entry{Start: storage.ToUTC(start), Text: text}
+	endValue := strings.TrimSpace(fields[editFieldEnd])
+	if endValue == "" {
+		return entry, nil
+	}
+
+	end, err := parseEditTime(endValue, start, tz)
+	if err != nil {
+		return storage.Entry{}, fmt.Errorf("end: %w", err)
+	}
+	if _, _, err := storage.ParseTimeOfDay(endValue); err == nil && !end.After(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+	if !end.After(start) {
+		return storage.Entry{}, &invalidTimeError{msg: "end time must be after start time"}
+	}
+	if end.After(now) {
+		return storage.Entry{}, &invalidTimeError{msg: "end time cannot be in the future"}
+	}
+	endUTC := storage.ToUTC(end)
+	entry.End = &endUTC
+	return entry, nil
+}
+
+// parseEditTime parses "YYYY-MM-DD HH:MM", or "HH:MM" on base's day.
+func parseEditTime(value string, base time.Time, tz *time.Location) (time.Time, error) {
+	if t, err := time.ParseInLocation(editTimeLayout, value, tz); err == nil {
+		return t, nil
+	}
+	hour, minute, err := storage.ParseTimeOfDay(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("use YYYY-MM-DD HH:MM or HH:MM")
+	}
+	baseLocal := base.In(tz)
+	return time.Date(baseLocal.Year(), baseLocal.Month(), baseLocal.Day(), hour, minute, 0, 0, tz), nil
+}
@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"lazytime/storage"
+)
+
+func TestParseEditFields(t *testing.T) {
+	tz := time.FixedZone("UTC+1", 3600)
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2024, month, day, hour, minute, 0, 0, tz)
+	}
+	now := at(3, 1, 18, 0)
+	target := storage.Entry{Start: at(3, 1, 9, 0).UTC(), Text: "Old"}
+
+	tests := []struct {
+		name      string
+		fields    []string
+		wantStart time.Time
+		wantEnd   time.Time // zero for a running entry
+		wantErr   string
+	}{
+		{"times on the target's day", []string{"Work", "10:00", "12:00"}, at(3, 1, 10, 0), at(3, 1, 12, 0), ""},
+		{"empty end keeps it running", []string{"Work", "10:00", ""}, at(3, 1, 10, 0), time.Time{}, ""},
+		{"full dates", []string{"Work", "2024-02-28 22:00", "2024-02-29 02:00"}, at(2, 28, 22, 0), at(2, 29, 2, 0), ""},
+		{"end time before start rolls to the next day", []string{"Work", "2024-02-29 22:00", "01:00"}, at(2, 29, 22, 0), at(3, 1, 1, 0), ""},
+		{"end time equal to start rolls to the next day", []string{"Work", "2024-02-28 09:00", "09:00"}, at(2, 28, 9, 0), at(2, 29, 9, 0), ""},
+		{"dated end before start", []string{"Work", "10:00", "2024-03-01 09:00"}, time.Time{}, time.Time{}, "end time must be after start time"},
+		{"rolled end in the future", []string{"Work", "17:00", "09:00"}, time.Time{}, time.Time{}, "end time cannot be in the future"},
+		{"start in the future", []string{"Work", "19:00", ""}, time.Time{}, time.Time{}, "start time cannot be in the future"},
+		{"invalid start", []string{"Work", "9am", ""}, time.Time{}, time.Time{}, "start: use YYYY-MM-DD HH:MM or HH:MM"},
+		{"empty text", []string{" ", "10:00", ""}, time.Time{}, time.Time{}, "please enter a description"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := parseEditFields(tt.fields, target, now)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseEditFields failed: %v", err)
+			}
+			if !entry.Start.Equal(tt.wantStart) {
+				t.Errorf("Start = %v, want %v", entry.Start.In(tz), tt.wantStart)
+			}
+			if tt.wantEnd.IsZero() != (entry.End == nil) || (entry.End != nil && !entry.End.Equal(tt.wantEnd)) {
+				t.Errorf("End = %v, want %v", entry.End, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestEditAndNewEntryShareTimeZone(t *testing.T) {
+	tz := time.FixedZone("UTC-5", -5*3600)
+	now := time.Date(2024, 3, 1, 18, 0, 0, 0, tz)
+	target := storage.Entry{Start: time.Date(2024, 3, 1, 8, 0, 0, 0, tz).UTC(), Text: "Work"}
+
+	edited, err := parseEditFields([]string{"Work", "09:00", ""}, target, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, start, _, err := parseTimeOverrides("Work @09:00", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !edited.Start.Equal(*start) {
+		t.Errorf("Edited 09:00 is %v but @09:00 is %v", edited.Start, start.UTC())
+	}
+}
@@ -9,12 +9,17 @@ import (
 )
 
 // TagGroup represents entries grouped by tag.
+// Hierarchical tags produce one group per level; parent groups roll up
+// the durations of their children.
 type TagGroup struct {
-	Tag       string
-	Duration  time.Duration
-	Entries   []storage.Entry
-	Tasks     map[string]time.Duration // Task text -> duration
-	TaskList  []TaskItem               // Sorted task items
+	Tag         string // Full normalized tag path, e.g. "clientA/api"
+	Name        string // Last level of the tag, e.g. "api"
+	Depth       int    // Nesting level (0 for root tags)
+	HasChildren bool
+	Duration    time.Duration
+	Entries     []storage.Entry          // Entries tagged with exactly this tag
+	Tasks       map[string]time.Duration // Task text -> duration
+	TaskList    []TaskItem               // Sorted task items
 }
 
 // TaskItem represents a task with its duration.
@@ -26,7 +31,11 @@ type TaskItem struct {
 }
 
 // GroupByTag groups entries by tag and calculates totals.
-func GroupByTag(entries []storage.Entry, startUTC, endUTC, now time.Time) []TagGroup {
+// Tags are matched by storage.TagKey and keep the spelling they were first
+// seen with. Durations are rounded according to the given policy.
+// The result is ordered as a depth-first tree: each group is followed by its
+// children, and siblings are sorted by duration (descending), then by tag.
+func GroupByTag(entries []storage.Entry, startUTC, endUTC, now time.Time, rounding storage.Rounding) []TagGroup {
 	tagMap := make(map[string]*TagGroup)
 
 	for _, entry := range entries {
@@ -34,26 +43,45 @@ func GroupByTag(entries []storage.Entry, startUTC, endUTC, now time.Time) []TagG
 		if duration <= 0 {
 			continue
 		}
+		duration = rounding.Entry(duration)
 
-		tags := entry.Tags()
+		// Tags the entry carries directly (tasks are listed under these only)
+		direct := make(map[string]bool)
+		for _, tag := range entry.Tags() {
+			direct[storage.TagKey(tag)] = true
+		}
+
+		tags := entry.TagPaths()
 		if len(tags) == 0 {
 			tags = []string{"(untagged)"}
+			direct["(untagged)"] = true
 		}
 
+		seen := make(map[string]bool)
 		for _, tag := range tags {
-			group, exists := tagMap[tag]
+			key := storage.TagKey(tag)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			group, exists := tagMap[key]
 			if !exists {
 				group = &TagGroup{
 					Tag:      tag,
+					Name:     storage.TagName(tag),
+					Depth:    storage.TagDepth(tag),
 					Duration: 0,
 					Entries:  []storage.Entry{},
 					Tasks:    make(map[string]time.Duration),
 					TaskList: []TaskItem{},
 				}
-				tagMap[tag] = group
+				tagMap[key] = group
 			}
 
 			group.Duration += duration
+			if !direct[key] {
+				continue
+			}
 			group.Entries = append(group.Entries, entry)
 
 			// Group by task text (without tags)
@@ -62,10 +90,14 @@ func GroupByTag(entries []storage.Entry, startUTC, endUTC, now time.Time) []TagG
 		}
 	}
 
-	// Convert to slice and sort by duration
-	var groups []TagGroup
-	for _, group := range tagMap {
-		// Build sorted task list
+	// Build sorted task lists and index children by parent
+	children := make(map[string][]*TagGroup)
+	for key, group := range tagMap {
+		group.Duration = rounding.Aggregate(group.Duration)
+		for taskText, taskDuration := range group.Tasks {
+			group.Tasks[taskText] = rounding.Aggregate(taskDuration)
+		}
+
 		for taskText, taskDuration := range group.Tasks {
 			// Find the first entry with this task text for start/end times
 			var taskStart, taskEnd time.Time
@@ -96,60 +128,109 @@ func GroupByTag(entries []storage.Entry, startUTC, endUTC, now time.Time) []TagG
 			return group.TaskList[i].Text < group.TaskList[j].Text
 		})
 
-		groups = append(groups, *group)
+		parent := storage.TagParent(key)
+		children[parent] = append(children[parent], group)
 	}
 
-	// Sort groups by duration (descending), then alphabetically by tag
-	sort.Slice(groups, func(i, j int) bool {
-		if groups[i].Duration != groups[j].Duration {
-			return groups[i].Duration > groups[j].Duration
+	// Flatten the tree depth-first, sorting siblings by duration (descending),
+	// then alphabetically by tag
+	var groups []TagGroup
+	var walk func(parent string)
+	walk = func(parent string) {
+		siblings := children[parent]
+		sort.Slice(siblings, func(i, j int) bool {
+			if siblings[i].Duration != siblings[j].Duration {
+				return siblings[i].Duration > siblings[j].Duration
+			}
+			return siblings[i].Tag < siblings[j].Tag
+		})
+		for _, group := range siblings {
+			key := storage.TagKey(group.Tag)
+			group.HasChildren = len(children[key]) > 0
+			groups = append(groups, *group)
+			walk(key)
 		}
-		return groups[i].Tag < groups[j].Tag
-	})
+	}
+	walk("")
 
 	return groups
 }
 
 // GroupByTagAndTask creates a two-level hierarchy (tag -> task).
-func GroupByTagAndTask(entries []storage.Entry, startUTC, endUTC, now time.Time) []TagGroup {
-	return GroupByTag(entries, startUTC, endUTC, now)
+func GroupByTagAndTask(entries []storage.Entry, startUTC, endUTC, now time.Time, rounding storage.Rounding) []TagGroup {
+	return GroupByTag(entries, startUTC, endUTC, now, rounding)
 }
 
 // CalculateTagTotals calculates total duration per tag.
-func CalculateTagTotals(entries []storage.Entry, startUTC, endUTC, now time.Time) map[string]time.Duration {
+// Tags are matched by storage.TagKey and keep the spelling they were first
+// seen with. Durations are rounded according to the given policy.
+func CalculateTagTotals(entries []storage.Entry, startUTC, endUTC, now time.Time, rounding storage.Rounding) map[string]time.Duration {
 	totals := make(map[string]time.Duration)
+	names := make(map[string]string) // Tag key -> first seen spelling
 	for _, entry := range entries {
 		duration := clampDuration(entry, startUTC, endUTC, now)
 		if duration <= 0 {
 			continue
 		}
+		duration = rounding.Entry(duration)
 		tags := entry.Tags()
 		if len(tags) == 0 {
 			tags = []string{"(untagged)"}
 		}
+		seen := make(map[string]bool)
 		for _, tag := range tags {
-			totals[tag] += duration
+			key := storage.TagKey(tag)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if _, ok := names[key]; !ok {
+				names[key] = storage.NormalizeTag(tag)
+			}
+			totals[names[key]] += duration
 		}
 	}
+	for tag, duration := range totals {
+		totals[tag] = rounding.Aggregate(duration)
+	}
 	return totals
 }
 
-// GetUniqueTags extracts all unique tags from entries.
-func GetUniqueTags(entries []storage.Entry) []string {
-	tagSet := make(map[string]bool)
+// DailyTotals returns the tracked time per local day ("2006-01-02", in
+// now's location) within [startUTC, endUTC). Entries spanning midnight
+// count toward each day they cover; open entries run until now.
+func DailyTotals(entries []storage.Entry, startUTC, endUTC, now time.Time) map[string]time.Duration {
+	tz := now.Location()
+	totals := make(map[string]time.Duration)
 	for _, entry := range entries {
-		tags := entry.Tags()
-		for _, tag := range tags {
-			tagSet[strings.ToLower(tag)] = true
+		end := now
+		if entry.End != nil {
+			end = *entry.End
+		}
+		start := entry.Start
+		if start.Before(startUTC) {
+			start = startUTC
+		}
+		if end.After(endUTC) {
+			end = endUTC
+		}
+		for start.Before(end) {
+			local := start.In(tz)
+			nextDay := time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, tz)
+			segmentEnd := end
+			if nextDay.Before(segmentEnd) {
+				segmentEnd = nextDay
+			}
+			totals[local.Format("2006-01-02")] += segmentEnd.Sub(start)
+			start = segmentEnd
 		}
 	}
+	return totals
+}
 
-	var tags []string
-	for tag := range tagSet {
-		tags = append(tags, tag)
-	}
-	sort.Strings(tags)
-	return tags
+// GetUniqueTags extracts all unique tags from entries.
+func GetUniqueTags(entries []storage.Entry) []string {
+	return storage.UniqueTags(entries)
 }
 
 // FilterEntriesByRange filters entries that overlap with the given time range.
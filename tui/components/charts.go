@@ -3,6 +3,7 @@ package components
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
@@ -18,23 +19,34 @@ type TagChartItem struct {
 // RenderTagChart renders a horizontal bar chart showing tag distribution.
 func RenderTagChart(totals map[string]time.Duration, width, height int, chartBarStyle, chartLabelStyle, chartPercentStyle, boxStyle lipgloss.Style, getTagColor func(string) lipgloss.Color, formatDurationShort func(time.Duration) string) string {
 	if len(totals) == 0 {
-		return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("No tags tracked."))
+		return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, lipgloss.NewStyle().Foreground(MutedColor).Render("No tags tracked."))
 	}
 
+	// Account for box padding (2 chars on each side = 4 total)
+	lines := TagChartLines(totals, width-4, height-2, chartBarStyle, chartLabelStyle, chartPercentStyle, getTagColor, formatDurationShort)
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	return boxStyle.Width(width).Height(height).Render(content)
+}
+
+// TagChartLines renders one bar per tag (at most maxLines, longest first)
+// in the tag's color, scaled to the longest, with the tag's share of the
+// total. The "(untagged)" bucket uses chartBarStyle.
+func TagChartLines(totals map[string]time.Duration, width, maxLines int, chartBarStyle, chartLabelStyle, chartPercentStyle lipgloss.Style, getTagColor func(string) lipgloss.Color, formatDurationShort func(time.Duration) string) []string {
 	// Convert to slice and sort
 	var items []TagChartItem
-	var maxDuration time.Duration
+	var maxDuration, total time.Duration
 	for tag, duration := range totals {
 		items = append(items, TagChartItem{Tag: tag, Duration: duration})
+		total += duration
 		if duration > maxDuration {
 			maxDuration = duration
 		}
 	}
 
-	// Calculate percentages
+	// Calculate each tag's share of the total
 	for i := range items {
-		if maxDuration > 0 {
-			items[i].Percent = float64(items[i].Duration) / float64(maxDuration)
+		if total > 0 {
+			items[i].Percent = float64(items[i].Duration) / float64(total)
 		}
 	}
 
@@ -47,58 +59,170 @@ func RenderTagChart(totals map[string]time.Duration, width, height int, chartBar
 	})
 
 	// Limit to available height
-	maxLines := height - 2
 	if len(items) > maxLines {
-		items = items[:maxLines]
+		items = items[:max(0, maxLines)]
+	}
+
+	// Space for percentage and duration (e.g., " 45%  12h 30m")
+	valueTexts := make([]string, len(items))
+	valueWidth := 0
+	for i, item := range items {
+		valueTexts[i] = fmt.Sprintf(" %3d%%  %s", int(item.Percent*100+0.5), formatDurationShort(item.Duration))
+		valueWidth = max(valueWidth, len(valueTexts[i]))
 	}
 
 	var lines []string
-	tagNameWidth := 20 // Increased width for tag names with # prefix
-	percentWidth := 6  // Space for percentage (e.g., "100%")
-	barWidth := width - tagNameWidth - percentWidth - 2 // Leave space for tag name, percentage, and padding
-
-	for _, item := range items {
-		filled := int(float64(barWidth) * item.Percent)
-		if filled < 0 {
-			filled = 0
+	tagNameWidth := 20 // Width for tag names with # prefix
+	barWidth := max(1, width-tagNameWidth-valueWidth)
+
+	for i, item := range items {
+		filled := 0
+		if maxDuration > 0 {
+			filled = min(barWidth, max(0, int(float64(barWidth)*float64(item.Duration)/float64(maxDuration))))
 		}
-		if filled > barWidth {
-			filled = barWidth
+		if filled == 0 && item.Duration > 0 {
+			filled = 1 // Keep every tracked tag visible
 		}
 
-		bar := ""
-		for i := 0; i < filled; i++ {
-			bar += "█"
+		barStyle := chartBarStyle
+		name := item.Tag
+		if item.Tag != "(untagged)" {
+			barStyle = lipgloss.NewStyle().Foreground(getTagColor(item.Tag))
+			// Add # prefix to match UI style
+			name = "#" + item.Tag
 		}
-
-		tagColor := getTagColor(item.Tag)
-		tagStyle := chartLabelStyle.Copy().Foreground(tagColor)
-		// Add # prefix to match UI style
-		tagNameWithPrefix := "#" + item.Tag
-		tagName := tagStyle.Render(tagNameWithPrefix)
-		// Truncate if needed (accounting for # prefix and ANSI codes)
-		visibleWidth := lipgloss.Width(tagName)
-		if visibleWidth > tagNameWidth {
-			// Truncate the tag name (without #) and add back the prefix
-			maxTagLen := tagNameWidth - 1 // -1 for the # character
-			if maxTagLen > 0 && len(item.Tag) > maxTagLen {
-				truncatedTag := item.Tag[:maxTagLen-3] + "..."
-				tagName = tagStyle.Render("#" + truncatedTag)
-			}
+		// Truncate if needed
+		if len([]rune(name)) > tagNameWidth-1 {
+			name = string([]rune(name)[:tagNameWidth-4]) + "..."
 		}
+		tagName := chartLabelStyle.Copy().Foreground(barStyle.GetForeground()).Render(name)
 
-		percentNum := int(item.Percent * 100)
-		percentText := chartPercentStyle.Render(fmt.Sprintf("%d%%", percentNum))
-		barStyled := chartBarStyle.Render(bar)
+		bar := barStyle.Render(strings.Repeat("█", filled)) + strings.Repeat(" ", barWidth-filled)
+		valueText := chartPercentStyle.Render(valueTexts[i])
 
 		line := lipgloss.JoinHorizontal(lipgloss.Left,
 			lipgloss.NewStyle().Width(tagNameWidth).Render(tagName),
-			barStyled,
-			percentText,
+			bar,
+			valueText,
 		)
 		lines = append(lines, line)
 	}
+	return lines
+}
 
-	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
-	return boxStyle.Width(width).Height(height).Render(content)
+// StackSegment is the time tracked for one tag within a stacked bar.
+type StackSegment struct {
+	Tag      string // "" for untagged time
+	Duration time.Duration
+}
+
+// StackedBar is one column of a stacked bar chart, e.g. a day.
+type StackedBar struct {
+	Label    string // Short label below the column
+	Segments []StackSegment
+}
+
+// StackedBarLines renders bars as vertical columns of height rows, each
+// stacked bottom-up from its segments in their tag's colors and scaled to
+// the tallest bar. Columns are as wide as fits in width (up to 6); labels
+// are shown below the columns where they fit.
+func StackedBarLines(bars []StackedBar, width, height int, labelStyle lipgloss.Style, getTagColor func(string) lipgloss.Color) []string {
+	if len(bars) == 0 || height < 1 {
+		return nil
+	}
+	columnWidth := max(1, min(6, width/len(bars)))
+	barWidth := max(1, columnWidth-1) // Leave a space between columns
+	if columnWidth == 1 {
+		barWidth = 1
+	}
+
+	var maxTotal time.Duration
+	for _, bar := range bars {
+		var total time.Duration
+		for _, segment := range bar.Segments {
+			total += segment.Duration
+		}
+		if total > maxTotal {
+			maxTotal = total
+		}
+	}
+	if maxTotal == 0 {
+		return []string{labelStyle.Render("No time tracked.")}
+	}
+
+	// Color of each cell, filled bottom-up by the cumulative segment totals
+	rows := make([]strings.Builder, height)
+	for _, bar := range bars {
+		var cumulative time.Duration
+		levels := make([]string, height) // Tag per row from the bottom; "" means empty
+		filled := make([]bool, height)
+		for _, segment := range bar.Segments {
+			from := int(float64(height) * float64(cumulative) / float64(maxTotal))
+			cumulative += segment.Duration
+			to := int(float64(height)*float64(cumulative)/float64(maxTotal) + 0.5)
+			for level := from; level < min(height, to); level++ {
+				if !filled[level] {
+					levels[level] = segment.Tag
+					filled[level] = true
+				}
+			}
+		}
+		for row := 0; row < height; row++ {
+			level := height - 1 - row
+			cell := strings.Repeat(" ", barWidth)
+			if filled[level] {
+				color := MutedColor
+				if levels[level] != "" {
+					color = getTagColor(levels[level])
+				}
+				cell = lipgloss.NewStyle().Foreground(color).Render(strings.Repeat("█", barWidth))
+			}
+			rows[row].WriteString(cell + strings.Repeat(" ", columnWidth-barWidth))
+		}
+	}
+
+	lines := make([]string, 0, height+1)
+	for i := range rows {
+		lines = append(lines, rows[i].String())
+	}
+
+	// Labels start below their column and are skipped where the previous
+	// label is still running
+	labels := []rune(strings.Repeat(" ", len(bars)*columnWidth))
+	free := 0
+	for i, bar := range bars {
+		pos := i * columnWidth
+		label := []rune(bar.Label)
+		if pos < free || pos+len(label) > len(labels) {
+			continue
+		}
+		copy(labels[pos:], label)
+		free = pos + len(label) + 1
+	}
+	lines = append(lines, labelStyle.Render(string(labels)))
+	return lines
+}
+
+// sparkLevels are the block characters of a sparkline, lowest first.
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// RenderSparkline renders values as a line of block characters scaled to
+// the largest value; zero values are shown as spaces.
+func RenderSparkline(values []time.Duration) string {
+	var maxValue time.Duration
+	for _, value := range values {
+		if value > maxValue {
+			maxValue = value
+		}
+	}
+	var line strings.Builder
+	for _, value := range values {
+		if value <= 0 || maxValue == 0 {
+			line.WriteRune(' ')
+			continue
+		}
+		level := int(float64(len(sparkLevels)-1) * float64(value) / float64(maxValue))
+		line.WriteRune(sparkLevels[level])
+	}
+	return line.String()
 }
@@ -0,0 +1,52 @@
+package components
+
+import (
+	"fmt"
+	"lazytime/storage"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RenderGapList renders untracked gaps with their day, time range and
+// length, followed by the total. The gap at index cursor is highlighted
+// with selectedStyle and kept in view.
+func RenderGapList(gaps []storage.Gap, tz *time.Location, width, height, cursor int, labelStyle, gapStyle, selectedStyle, boxStyle lipgloss.Style, formatDurationShort func(time.Duration) string) string {
+	if len(gaps) == 0 {
+		return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, lipgloss.NewStyle().Foreground(MutedColor).Render("No gaps within working hours."))
+	}
+
+	// Account for box padding (2 chars on each side = 4 total)
+	lineWidth := width - 4
+
+	var lines []string
+	var total time.Duration
+	for i, gap := range gaps {
+		startLocal := gap.Start.In(tz)
+		line := fmt.Sprintf("%s  %s - %s", startLocal.Format("Mon 01-02"), startLocal.Format("15:04"), gap.End.In(tz).Format("15:04"))
+		durationText := formatDurationShort(gap.Duration())
+		line += strings.Repeat(" ", max(1, lineWidth-len(line)-len(durationText)-2))
+
+		if i == cursor {
+			lines = append(lines, selectedStyle.Width(lineWidth).Render("> "+line+durationText))
+		} else {
+			lines = append(lines, "  "+line+gapStyle.Render(durationText))
+		}
+		total += gap.Duration()
+	}
+
+	// Scroll so the cursor stays visible, keeping room for the total
+	maxLines := max(1, height-2-2)
+	startIdx := 0
+	if cursor >= maxLines {
+		startIdx = cursor - maxLines + 1
+	}
+	lines = lines[startIdx:min(len(lines), startIdx+maxLines)]
+
+	lines = append(lines, "")
+	lines = append(lines, labelStyle.Render(fmt.Sprintf("Untracked: %s in %d gaps  (Enter to log)", formatDurationShort(total), len(gaps))))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	return boxStyle.Width(width).Height(height).Render(content)
+}
@@ -60,18 +60,7 @@ func RenderWeekHeatmap(entries []storage.Entry, now time.Time, width, height int
 		}
 
 		// Choose color based on intensity
-		var color lipgloss.Color
-		if intensity == 0 {
-			color = lipgloss.Color("#333333")
-		} else if intensity < 0.25 {
-			color = lipgloss.Color("#005500")
-		} else if intensity < 0.5 {
-			color = lipgloss.Color("#00aa00")
-		} else if intensity < 0.75 {
-			color = lipgloss.Color("#00ff00")
-		} else {
-			color = lipgloss.Color("#88ff88")
-		}
+		color := heatmapColor(intensity)
 
 		square := lipgloss.NewStyle().
 			Background(color).
@@ -83,7 +72,7 @@ func RenderWeekHeatmap(entries []storage.Entry, now time.Time, width, height int
 		squares = append(squares, square)
 		if i < len(dayNames) {
 			// Add day name below
-			dayName := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render(dayNames[i])
+			dayName := lipgloss.NewStyle().Foreground(MutedColor).Render(dayNames[i])
 			squares = append(squares, "\n"+dayName)
 		}
 	}
@@ -121,34 +110,11 @@ func RenderMonthHeatmap(entries []storage.Entry, now time.Time, width, height in
 		}
 	}
 
-	// Calculate available space (accounting for box padding: 1 top/bottom, 2 left/right)
-	boxPaddingH := 2 * 2 // left + right padding
-	boxPaddingV := 1 * 2 // top + bottom padding
-	headerHeight := 2    // "Last 30 Days" + empty line
-	spacing := 1         // spacing between squares
-
-	availableWidth := width - boxPaddingH
-	availableHeight := height - boxPaddingV - headerHeight
-
-	// Fixed grid layout: 6 columns, 5 rows (30 days total)
-	const numDays = 30
-	const cols = 6
-	const rows = 5
-
-	// Calculate square dimensions that fit
-	// Available width: (cols * squareWidth) + ((cols - 1) * spacing) <= availableWidth
-	// squareWidth <= (availableWidth - (cols-1)*spacing) / cols
-	squareWidth := (availableWidth - (cols-1)*spacing) / cols
-	if squareWidth < 2 {
-		squareWidth = 2
-	}
-
-	// Available height: (rows * squareHeight) + ((rows - 1) * spacing) <= availableHeight
-	// squareHeight <= (availableHeight - (rows-1)*spacing) / rows
-	squareHeight := (availableHeight - (rows-1)*spacing) / rows
-	if squareHeight < 2 {
-		squareHeight = 2
-	}
+	squareWidth, squareHeight := monthHeatmapSquareSize(width, height)
+	const numDays = monthHeatmapDays
+	const cols = monthHeatmapCols
+	const rows = monthHeatmapRows
+	spacing := monthHeatmapSpacing
 
 	// Render header
 	var lines []string
@@ -181,18 +147,7 @@ func RenderMonthHeatmap(entries []storage.Entry, now time.Time, width, height in
 					intensity = float64(total) / float64(maxDuration)
 				}
 
-				var color lipgloss.Color
-				if intensity == 0 {
-					color = lipgloss.Color("#333333")
-				} else if intensity < 0.25 {
-					color = lipgloss.Color("#005500")
-				} else if intensity < 0.5 {
-					color = lipgloss.Color("#00aa00")
-				} else if intensity < 0.75 {
-					color = lipgloss.Color("#00ff00")
-				} else {
-					color = lipgloss.Color("#88ff88")
-				}
+				color := heatmapColor(intensity)
 
 				// Create square with proper width
 				squareContent := strings.Repeat("█", squareWidth)
@@ -221,3 +176,47 @@ func RenderMonthHeatmap(entries []storage.Entry, now time.Time, width, height in
 	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
 	return boxStyle.Width(width).Height(height).Render(content)
 }
+
+// Fixed grid layout of the month heatmap: 6 columns, 5 rows (30 days total)
+const (
+	monthHeatmapDays    = 30
+	monthHeatmapCols    = 6
+	monthHeatmapRows    = 5
+	monthHeatmapSpacing = 1 // Spacing between squares
+	monthHeatmapHeader  = 2 // "Last 30 Days" + empty line
+)
+
+// MonthHeatmapMinHeight is the smallest height RenderMonthHeatmap draws
+// every square in: box padding, header and one-line squares.
+const MonthHeatmapMinHeight = 2 + monthHeatmapHeader + monthHeatmapRows + (monthHeatmapRows-1)*monthHeatmapSpacing
+
+// monthHeatmapSquareSize returns the size of the month heatmap squares that
+// fit in a box of width and height.
+func monthHeatmapSquareSize(width, height int) (int, int) {
+	// Calculate available space (accounting for box padding: 1 top/bottom, 2 left/right)
+	availableWidth := width - 2*2
+	availableHeight := height - 1*2 - monthHeatmapHeader
+
+	// Available width: (cols * squareWidth) + ((cols - 1) * spacing) <= availableWidth
+	squareWidth := max(2, (availableWidth-(monthHeatmapCols-1)*monthHeatmapSpacing)/monthHeatmapCols)
+	// Available height: (rows * squareHeight) + ((rows - 1) * spacing) <= availableHeight
+	squareHeight := max(1, (availableHeight-(monthHeatmapRows-1)*monthHeatmapSpacing)/monthHeatmapRows)
+	return squareWidth, squareHeight
+}
+
+// MonthHeatmapDayAt returns how many days before today the square at
+// content position (x, y) of a heatmap rendered by RenderMonthHeatmap with
+// width and height shows, or false if (x, y) is not on a square.
+func MonthHeatmapDayAt(width, height, x, y int) (int, bool) {
+	squareWidth, squareHeight := monthHeatmapSquareSize(width, height)
+	y -= monthHeatmapHeader
+	if x < 0 || y < 0 {
+		return 0, false
+	}
+	col, colOffset := x/(squareWidth+monthHeatmapSpacing), x%(squareWidth+monthHeatmapSpacing)
+	row, rowOffset := y/(squareHeight+monthHeatmapSpacing), y%(squareHeight+monthHeatmapSpacing)
+	if col >= monthHeatmapCols || row >= monthHeatmapRows || colOffset >= squareWidth || rowOffset >= squareHeight {
+		return 0, false
+	}
+	return monthHeatmapDays - 1 - (row*monthHeatmapCols + col), true
+}
@@ -0,0 +1,81 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// HelpItem is one line of the help: keys and what they do.
+type HelpItem struct {
+	Keys string
+	Desc string
+}
+
+// HelpSection is a titled group of help items followed by free text lines.
+type HelpSection struct {
+	Title string
+	Items []HelpItem
+	Lines []string
+}
+
+// helpKeyWidth caps the key column of the help so long key lists do not
+// push the descriptions out of the modal.
+const helpKeyWidth = 14
+
+// HelpLines lays out the help sections with the keys in an aligned column.
+func HelpLines(sections []HelpSection, titleStyle, keyStyle lipgloss.Style) []string {
+	keyWidth := 0
+	for _, section := range sections {
+		for _, item := range section.Items {
+			keyWidth = max(keyWidth, min(helpKeyWidth, lipgloss.Width(item.Keys)))
+		}
+	}
+
+	var lines []string
+	for i, section := range sections {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, titleStyle.Render(section.Title+":"))
+		for _, item := range section.Items {
+			keys := item.Keys
+			if pad := keyWidth - lipgloss.Width(keys); pad > 0 {
+				keys += strings.Repeat(" ", pad)
+			}
+			lines = append(lines, "  "+keyStyle.Render(keys)+"  "+item.Desc)
+		}
+		for _, line := range section.Lines {
+			lines = append(lines, "  "+line)
+		}
+	}
+	return lines
+}
+
+// HelpVisibleLines returns how many help lines the help modal shows on a
+// screen of the given height.
+func HelpVisibleLines(height int) int {
+	// Screen margin, border, padding, title and footer
+	return max(1, height-4-8)
+}
+
+// RenderHelpModal renders the help modal showing lines from offset on,
+// with hint (the scroll and close keys) at the bottom.
+func RenderHelpModal(lines []string, offset int, hint string, width, height int, boxStyle, footerStyle lipgloss.Style) string {
+	modalWidth := min(72, width-4)
+	visible := HelpVisibleLines(height)
+	offset = min(max(0, offset), max(0, len(lines)-visible))
+	end := min(len(lines), offset+visible)
+
+	content := []string{lipgloss.NewStyle().Bold(true).Render("Help"), ""}
+	for _, line := range lines[offset:end] {
+		content = append(content, ansi.Truncate(line, modalWidth-4, "…"))
+	}
+	for i := end - offset; i < visible; i++ {
+		content = append(content, "")
+	}
+	content = append(content, "", footerStyle.Render(hint))
+
+	return renderModalBox(content, modalWidth, len(content)+2, boxStyle)
+}
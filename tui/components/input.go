@@ -0,0 +1,14 @@
+package components
+
+import "github.com/charmbracelet/lipgloss"
+
+// RenderInput renders a text input value with the cursor at rune index
+// cursor highlighted by cursorStyle.
+func RenderInput(value string, cursor int, cursorStyle lipgloss.Style) string {
+	runes := []rune(value)
+	cursor = min(max(0, cursor), len(runes))
+	if cursor == len(runes) {
+		return value + cursorStyle.Render(" ")
+	}
+	return string(runes[:cursor]) + cursorStyle.Render(string(runes[cursor])) + string(runes[cursor+1:])
+}
@@ -1,6 +1,7 @@
 package components
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -35,99 +36,136 @@ func GetFuzzySuggestions(input string, allTags []string, limit int) []string {
 	return matches
 }
 
-// RenderModal renders a modal dialog for input.
-func RenderModal(modalType, input string, suggestions []string, selected int, width, height int, boxStyle, tabActive, tabInactive, footerStyle lipgloss.Style) string {
+// RenderModal renders a modal dialog for input, which is already rendered
+// with its cursor (see RenderInput). The prompt is shown by the
+// confirm and split modals, errText below the input when set and hint
+// at the bottom.
+func RenderModal(modalType, prompt, input, errText, hint string, suggestions []string, selected int, width, height int, boxStyle, tabActive, tabInactive, footerStyle, errorStyle lipgloss.Style) string {
 	modalWidth := min(60, width-4)
-	modalHeight := 12
-	if modalType == "help" {
-		modalHeight = min(25, height-4)
-	}
+	modalHeight := min(12, height-4)
 
-	startX := (width - modalWidth) / 2
-	startY := (height - modalHeight) / 2
+	var lines []string
+	switch modalType {
+	case "confirm":
+		lines = append(lines, boxStyle.Bold(true).Render("Confirm"))
+		lines = append(lines, "")
+		lines = append(lines, prompt)
+		lines = append(lines, "")
+		lines = append(lines, footerStyle.Render(hint))
+		return renderModalBox(lines, modalWidth, modalHeight, boxStyle)
+	case "idle":
+		lines = append(lines, boxStyle.Bold(true).Render("Idle Time"))
+		lines = append(lines, "")
+		lines = append(lines, prompt)
+		lines = append(lines, "")
+		lines = append(lines, footerStyle.Render(hint))
+		return renderModalBox(lines, modalWidth, modalHeight, boxStyle)
+	case "reassign":
+		lines = append(lines, boxStyle.Bold(true).Render("Reassign Idle Time"))
+		lines = append(lines, "")
+		lines = append(lines, prompt)
+		lines = append(lines, input)
+	case "filter":
+		lines = append(lines, boxStyle.Bold(true).Render("Filter by Tag"))
+		lines = append(lines, "")
+		lines = append(lines, "#"+input)
+		if len(suggestions) == 0 {
+			lines = append(lines, "")
+			lines = append(lines, "No matching tags")
+		}
+	case "split":
+		lines = append(lines, boxStyle.Bold(true).Render("Split Entry"))
+		lines = append(lines, "")
+		lines = append(lines, prompt)
+		lines = append(lines, input)
+	default:
+		// New entry modal
+		lines = append(lines, boxStyle.Bold(true).Render("Start New Entry"))
+		lines = append(lines, "")
+		lines = append(lines, input)
+		lines = append(lines, "")
+		lines = append(lines, "Tips:")
+		lines = append(lines, "  • Tags: use #tag format (e.g., #project #work)")
+		lines = append(lines, "  • Time: use @HH:MM for start time, @HH:MM @HH:MM for completed entry")
+	}
 
-	if modalType == "help" {
-		return renderHelpModal(modalWidth, modalHeight, startX, startY, boxStyle)
+	if errText != "" {
+		lines = append(lines, "")
+		lines = append(lines, errorStyle.Render("Error: "+errText))
 	}
+	lines = append(lines, renderSuggestions(suggestions, selected, tabActive, tabInactive)...)
 
-	// New entry modal
+	lines = append(lines, "")
+	lines = append(lines, footerStyle.Render(hint))
+
+	return renderModalBox(lines, modalWidth, modalHeight, boxStyle)
+}
+
+// RenderEditModal renders the edit modal with text, start and end fields.
+// The active field is rendered with its cursor by the caller; errText is
+// shown below the fields and hint at the bottom.
+func RenderEditModal(fields []string, active int, errText, hint string, suggestions []string, selected int, width, height int, boxStyle, tabActive, tabInactive, footerStyle, errorStyle lipgloss.Style) string {
+	modalWidth := min(60, width-4)
+	modalHeight := min(16, height-4)
+
+	labels := []string{"Text", "Start", "End"}
 	var lines []string
-	lines = append(lines, boxStyle.Bold(true).Render("Start New Entry"))
+	lines = append(lines, boxStyle.Bold(true).Render("Edit Entry"))
 	lines = append(lines, "")
-	lines = append(lines, input+"_") // Cursor indicator
+	for i, label := range labels {
+		if i >= len(fields) {
+			break
+		}
+		value := fields[i]
+		marker := "  "
+		if i == active {
+			marker = "> "
+		}
+		lines = append(lines, fmt.Sprintf("%s%-7s%s", marker, label+":", value))
+	}
 	lines = append(lines, "")
-	lines = append(lines, "Tips:")
-	lines = append(lines, "  • Tags: use #tag format (e.g., #project #work)")
-	lines = append(lines, "  • Time: use @HH:MM for start time, @HH:MM @HH:MM for completed entry")
+	lines = append(lines, "Times: YYYY-MM-DD HH:MM or HH:MM (empty End = running)")
 
-	// Show suggestions if available
-	if len(suggestions) > 0 {
+	if errText != "" {
 		lines = append(lines, "")
-		lines = append(lines, "Suggestions:")
-		for i, sug := range suggestions {
-			if i >= 5 {
-				break
-			}
-			style := tabInactive
-			if i == selected {
-				style = tabActive
-			}
-			lines = append(lines, "  "+style.Render("#"+sug))
-		}
+		lines = append(lines, errorStyle.Render("Error: "+errText))
 	}
+	lines = append(lines, renderSuggestions(suggestions, selected, tabActive, tabInactive)...)
 
 	lines = append(lines, "")
-	lines = append(lines, footerStyle.Render("Enter: Confirm  Esc: Cancel"))
-
-	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
-	modal := boxStyle.
-		Width(modalWidth).
-		Height(modalHeight).
-		BorderForeground(lipgloss.Color("#00ff00")).
-		Render(content)
+	lines = append(lines, footerStyle.Render(hint))
 
-	// Position the modal
-	positioned := lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, modal)
-	return positioned
+	return renderModalBox(lines, modalWidth, modalHeight, boxStyle)
 }
 
-// renderHelpModal renders the help modal.
-func renderHelpModal(width, height, startX, startY int, boxStyle lipgloss.Style) string {
-	helpText := []string{
-		"TUI Usage:",
-		"",
-		"Navigation:",
-		"  1/2      - Switch view (Today/Week)",
-		"  ↑/↓      - Scroll active pane",
-		"",
-		"Actions:",
-		"  n        - Start new entry",
-		"  x        - Stop current entry",
-		"  r        - Reload log file",
-		"  q/Esc    - Quit",
-		"  e/?      - Show this help",
-		"",
-		"Time Overrides:",
-		"  @HH:MM   - Backdate start time for today",
-		"  @HH:MM @HH:MM - Add completed entry",
-		"  Example: \"Task @09:00\" or \"Task @09:00 @10:30\"",
-		"",
-		"Tags & Labels:",
-		"  Tags are words starting with # in entry text",
-		"  Example: \"Write docs #project #writing\"",
-		"  Multiple tags allowed per entry",
-		"",
-		"Press Esc/q/e to close",
+// renderSuggestions renders the suggestion list, if any.
+func renderSuggestions(suggestions []string, selected int, tabActive, tabInactive lipgloss.Style) []string {
+	if len(suggestions) == 0 {
+		return nil
 	}
+	lines := []string{"", "Suggestions:"}
+	for i, sug := range suggestions {
+		if i >= 5 {
+			break
+		}
+		style := tabInactive
+		if i == selected {
+			style = tabActive
+		}
+		lines = append(lines, "  "+style.Render(sug))
+	}
+	return lines
+}
 
-	content := lipgloss.JoinVertical(lipgloss.Left, helpText...)
-	modal := boxStyle.
-		Width(width).
-		Height(height).
-		BorderForeground(lipgloss.Color("#00ff00")).
+// renderModalBox draws the modal box; the caller overlays it on the
+// screen (see Overlay).
+func renderModalBox(lines []string, modalWidth, modalHeight int, boxStyle lipgloss.Style) string {
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	return boxStyle.
+		Width(modalWidth).
+		Height(modalHeight).
+		BorderForeground(ModalBorderColor).
 		Render(content)
-
-	return modal
 }
 
 func min(a, b int) int {
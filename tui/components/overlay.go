@@ -0,0 +1,44 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Overlay draws foreground centered over background, a screen of the given
+// width and height. The background is padded or cut to the screen size.
+func Overlay(background, foreground string, width, height int) string {
+	x := max(0, (width-lipgloss.Width(foreground))/2)
+	y := max(0, (height-lipgloss.Height(foreground))/2)
+	return PlaceOverlay(x, y, background, foreground, width, height)
+}
+
+// PlaceOverlay draws foreground over background with its top left corner
+// at column x and row y. Background cells left and right of each
+// foreground line keep their styles; the foreground starts from reset
+// styles so the background's colors do not leak into it.
+func PlaceOverlay(x, y int, background, foreground string, width, height int) string {
+	bgLines := strings.Split(background, "\n")
+	for len(bgLines) < height {
+		bgLines = append(bgLines, "")
+	}
+	bgLines = bgLines[:height]
+
+	for i, fgLine := range strings.Split(foreground, "\n") {
+		row := y + i
+		if row < 0 || row >= len(bgLines) {
+			continue
+		}
+		bgLine := bgLines[row]
+		if pad := width - ansi.StringWidth(bgLine); pad > 0 {
+			bgLine += strings.Repeat(" ", pad)
+		}
+		fgWidth := ansi.StringWidth(fgLine)
+		left := ansi.Truncate(bgLine, x, "")
+		right := ansi.TruncateLeft(bgLine, x+fgWidth, "")
+		bgLines[row] = left + ansi.ResetStyle + fgLine + ansi.ResetStyle + right
+	}
+	return strings.Join(bgLines, "\n")
+}
@@ -0,0 +1,33 @@
+package components
+
+import "github.com/charmbracelet/lipgloss"
+
+// Colors used by components for secondary text, labels and charts rather
+// than passed-in styles. The tui package sets them from the active theme.
+var (
+	// MutedColor is used for placeholders and secondary text.
+	MutedColor lipgloss.Color = "#888888"
+	// LabelColor is used for chart and progress labels.
+	LabelColor lipgloss.Color = "#ffffff"
+	// ModalBorderColor is the border color of modals.
+	ModalBorderColor lipgloss.Color = "#00ff00"
+	// HeatmapColors are the heatmap cell colors from no tracked time to the
+	// most (five levels).
+	HeatmapColors = []lipgloss.Color{"#333333", "#005500", "#00aa00", "#00ff00", "#88ff88"}
+)
+
+// heatmapColor returns the heatmap color for intensity in [0, 1].
+func heatmapColor(intensity float64) lipgloss.Color {
+	switch {
+	case intensity == 0:
+		return HeatmapColors[0]
+	case intensity < 0.25:
+		return HeatmapColors[1]
+	case intensity < 0.5:
+		return HeatmapColors[2]
+	case intensity < 0.75:
+		return HeatmapColors[3]
+	default:
+		return HeatmapColors[4]
+	}
+}
@@ -26,7 +26,7 @@ func formatDurationCompact(d time.Duration) string {
 // Uses a two-line layout: label + duration on top, bar on bottom.
 func RenderProgressBar(current, target time.Duration, label string, barWidth int, progressStyle lipgloss.Style) string {
 	if target <= 0 {
-		labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#ffffff"))
+		labelStyle := lipgloss.NewStyle().Bold(true).Foreground(LabelColor)
 		return labelStyle.Render(label + ": N/A")
 	}
 
@@ -41,8 +41,8 @@ func RenderProgressBar(current, target time.Duration, label string, barWidth int
 	durationText := currentStr + "/" + targetStr
 
 	// Style the label with duration (e.g., "Today: 5h/8h")
-	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#ffffff"))
-	durationStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(LabelColor)
+	durationStyle := lipgloss.NewStyle().Foreground(MutedColor)
 	styledLabel := labelStyle.Render(label + ":")
 	styledDuration := durationStyle.Render(" " + durationText)
 	firstLine := styledLabel + styledDuration
@@ -0,0 +1,79 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SearchResult is an entry matching the search query.
+type SearchResult struct {
+	Text     string
+	Start    time.Time
+	End      time.Time
+	Running  bool
+	Duration time.Duration
+}
+
+// searchVisibleResults is the number of results listed at once.
+const searchVisibleResults = 10
+
+// RenderSearchModal renders the search prompt (input is query rendered
+// with its cursor) and its results with their date, time range and
+// duration. The result at index selected is highlighted and kept in view,
+// and hint is shown at the bottom.
+func RenderSearchModal(query, input string, results []SearchResult, selected int, tz *time.Location, hint string, width, height int, boxStyle, selectedStyle, mutedStyle, footerStyle lipgloss.Style, formatDurationShort func(time.Duration) string) string {
+	modalWidth := min(90, width-4)
+	modalHeight := min(searchVisibleResults+12, height-4)
+	// Account for box padding (2 chars on each side = 4 total)
+	lineWidth := modalWidth - 4
+
+	var lines []string
+	lines = append(lines, boxStyle.Bold(true).Render("Search"))
+	lines = append(lines, "")
+	lines = append(lines, "/ "+input)
+	lines = append(lines, "")
+
+	switch {
+	case strings.TrimSpace(query) == "":
+		lines = append(lines, mutedStyle.Render("Type to search all entries by text or tag"))
+	case len(results) == 0:
+		lines = append(lines, mutedStyle.Render("No matching entries"))
+	default:
+		start := 0
+		if selected >= searchVisibleResults {
+			start = selected - searchVisibleResults + 1
+		}
+		for i := start; i < min(len(results), start+searchVisibleResults); i++ {
+			result := results[i]
+			startLocal := result.Start.In(tz)
+			endText := result.End.In(tz).Format("15:04")
+			if result.Running {
+				endText = "now"
+			}
+			line := fmt.Sprintf("%s  %s-%-5s %7s  %s",
+				startLocal.Format("2006-01-02 Mon"),
+				startLocal.Format("15:04"),
+				endText,
+				formatDurationShort(result.Duration),
+				result.Text)
+			if lipgloss.Width(line) > lineWidth-2 {
+				line = string([]rune(line)[:max(0, lineWidth-3)]) + "…"
+			}
+			if i == selected {
+				lines = append(lines, selectedStyle.Render("> "+line))
+			} else {
+				lines = append(lines, "  "+line)
+			}
+		}
+		lines = append(lines, "")
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("%d matches", len(results))))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, footerStyle.Render(hint))
+
+	return renderModalBox(lines, modalWidth, modalHeight, boxStyle)
+}
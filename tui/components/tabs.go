@@ -2,6 +2,7 @@ package components
 
 import (
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // ViewMode type for tabs (defined in tui package, passed as int)
@@ -10,11 +11,14 @@ type ViewMode int
 const (
 	ViewToday ViewMode = iota
 	ViewWeek
+	ViewMonth
+	ViewYear
 )
 
-// RenderTabs renders the tab navigation bar.
-func RenderTabs(activeView ViewMode, width int, tabActive, tabInactive lipgloss.Style) string {
-	tabs := []string{"Today", "Week"}
+// RenderTabs renders the tab navigation bar followed by the label of the
+// range shown by the active view and the active tag filter, if any.
+func RenderTabs(activeView ViewMode, rangeLabel, tagFilter string, width int, tabActive, tabInactive lipgloss.Style) string {
+	tabs := []string{"Today", "Week", "Month", "Year"}
 	var renderedTabs []string
 
 	for i, tab := range tabs {
@@ -25,5 +29,39 @@ func RenderTabs(activeView ViewMode, width int, tabActive, tabInactive lipgloss.
 		}
 	}
 
-	return lipgloss.JoinHorizontal(lipgloss.Left, renderedTabs...)
+	rangeTab, filterTab := "", ""
+	if rangeLabel != "" {
+		rangeTab = tabInactive.Render("‹ " + rangeLabel + " ›")
+	}
+	if tagFilter != "" {
+		filterTab = tabActive.Render("Filter: #" + tagFilter)
+	}
+
+	// Narrow windows drop the range, then the filter
+	tabsBar := lipgloss.JoinHorizontal(lipgloss.Left, renderedTabs...)
+	for _, bar := range []string{tabsBar + rangeTab + filterTab, tabsBar + filterTab} {
+		if lipgloss.Width(bar) <= width {
+			return bar
+		}
+	}
+	return ansi.Truncate(tabsBar, width, "")
+}
+
+// TabAt returns the view whose tab is at column x of the bar rendered by
+// RenderTabs, or false if x is not on a view tab.
+func TabAt(activeView ViewMode, x int, tabActive, tabInactive lipgloss.Style) (ViewMode, bool) {
+	tabs := []string{"Today", "Week", "Month", "Year"}
+	left := 0
+	for i, tab := range tabs {
+		style := tabInactive
+		if ViewMode(i) == activeView {
+			style = tabActive
+		}
+		right := left + lipgloss.Width(style.Render(tab))
+		if x >= left && x < right {
+			return ViewMode(i), true
+		}
+		left = right
+	}
+	return 0, false
 }
@@ -0,0 +1,222 @@
+package components
+
+import (
+	"fmt"
+	"lazytime/storage"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// timelineLabelWidth is the width of the day label before each bar.
+const timelineLabelWidth = 10
+
+// timelineTotalWidth is the width of the day total after each bar.
+const timelineTotalWidth = 8
+
+// timelineShortLabelWidth is the width of the day label on narrow panes.
+const timelineShortLabelWidth = 6
+
+// timelineMinBarWidth is the narrowest bar shown with a total and a full
+// label; narrower panes drop the total, then shorten the label.
+const timelineMinBarWidth = 12
+
+// TimelineDays returns the local start of each day in [startUTC, endUTC)
+// up to and including today, most recent first.
+func TimelineDays(startUTC, endUTC, now time.Time) []time.Time {
+	tz := now.Location()
+	startLocal := startUTC.In(tz)
+	day := time.Date(startLocal.Year(), startLocal.Month(), startLocal.Day(), 0, 0, 0, 0, tz)
+
+	var days []time.Time
+	for ; day.Before(endUTC) && !day.After(now); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	for i, j := 0, len(days)-1; i < j; i, j = i+1, j-1 {
+		days[i], days[j] = days[j], days[i]
+	}
+	return days
+}
+
+// RenderTimeline renders each day in [startUTC, endUTC) as a 24-hour bar,
+// most recent day first. Entries are colored by their first tag, untracked
+// gaps between the day's first and last entry are highlighted, and time
+// covered by more than one entry is marked as an overlap. The running entry
+// extends to now. scrollOffset is the index of the first day shown.
+func RenderTimeline(entries []storage.Entry, startUTC, endUTC, now time.Time, width, height, scrollOffset int, boxStyle, labelStyle, gapStyle, idleStyle, overlapStyle lipgloss.Style, getTagColor func(string) lipgloss.Color, formatDurationShort func(time.Duration) string) string {
+	days := TimelineDays(startUTC, endUTC, now)
+	if len(days) == 0 {
+		return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, lipgloss.NewStyle().Foreground(MutedColor).Render("No days in this period."))
+	}
+
+	// Account for box padding (2 chars on each side = 4 total)
+	lineWidth := width - 4
+	labelWidth, labelFormat, totalWidth := timelineLabelWidth, "Mon 01-02", timelineTotalWidth
+	if lineWidth-labelWidth-totalWidth < timelineMinBarWidth {
+		totalWidth = 0
+	}
+	if lineWidth-labelWidth < timelineMinBarWidth {
+		labelWidth, labelFormat = timelineShortLabelWidth, "01-02"
+	}
+	barWidth := max(1, lineWidth-labelWidth-totalWidth)
+
+	var lines []string
+	lines = append(lines, strings.Repeat(" ", labelWidth)+labelStyle.Render(timelineAxis(barWidth)))
+
+	// Reserve lines for the axis, the legend and the spacing between them
+	visibleDays := max(1, height-2-3)
+	scrollOffset = min(max(0, scrollOffset), max(0, len(days)-visibleDays))
+
+	usedTags := make(map[string]bool)
+	var tagOrder []string
+	for _, day := range days[scrollOffset:min(len(days), scrollOffset+visibleDays)] {
+		bar, total, tags := renderTimelineBar(entries, day, now, barWidth, gapStyle, idleStyle, overlapStyle, getTagColor)
+		for _, tag := range tags {
+			if !usedTags[tag] {
+				usedTags[tag] = true
+				tagOrder = append(tagOrder, tag)
+			}
+		}
+		label := labelStyle.Render(fmt.Sprintf("%-*s", labelWidth, day.Format(labelFormat)))
+		totalText := ""
+		if total > 0 && totalWidth > 0 {
+			totalText = " " + formatDurationShort(total)
+		}
+		lines = append(lines, label+bar+labelStyle.Render(totalText))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, renderTimelineLegend(tagOrder, lineWidth, gapStyle, overlapStyle, getTagColor))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	return boxStyle.Width(width).Height(height).Render(content)
+}
+
+// timelineAxis renders hour marks every 6 hours above a bar of barWidth cells.
+func timelineAxis(barWidth int) string {
+	axis := []rune(strings.Repeat(" ", barWidth))
+	for hour := 0; hour < 24; hour += 6 {
+		pos := hour * barWidth / 24
+		for i, r := range strconv.Itoa(hour) {
+			if pos+i < len(axis) {
+				axis[pos+i] = r
+			}
+		}
+	}
+	return string(axis)
+}
+
+// renderTimelineBar renders one day as barWidth cells and returns the bar,
+// the tracked total and the tags seen that day (in order of appearance).
+// Days with a daylight saving change are 23 or 25 hours long, so the cells
+// cover the day's actual length.
+func renderTimelineBar(entries []storage.Entry, day, now time.Time, barWidth int, gapStyle, idleStyle, overlapStyle lipgloss.Style, getTagColor func(string) lipgloss.Color) (string, time.Duration, []string) {
+	dayEnd := day.AddDate(0, 0, 1)
+	cellDuration := dayEnd.Sub(day) / time.Duration(barWidth)
+
+	// Clip entries to the day
+	type span struct {
+		start, end time.Time
+		tag        string
+	}
+	var spans []span
+	var total time.Duration
+	var tags []string
+	var firstStart, lastEnd time.Time
+	for _, entry := range entries {
+		end := now
+		if entry.End != nil {
+			end = *entry.End
+		}
+		start := entry.Start
+		if start.Before(day) {
+			start = day
+		}
+		if end.After(dayEnd) {
+			end = dayEnd
+		}
+		if !end.After(start) {
+			continue
+		}
+		tag := ""
+		if entryTags := entry.Tags(); len(entryTags) > 0 {
+			tag = strings.ToLower(entryTags[0])
+			tags = append(tags, tag)
+		}
+		spans = append(spans, span{start: start, end: end, tag: tag})
+		total += end.Sub(start)
+		if firstStart.IsZero() || start.Before(firstStart) {
+			firstStart = start
+		}
+		if end.After(lastEnd) {
+			lastEnd = end
+		}
+	}
+
+	var bar strings.Builder
+	for cell := 0; cell < barWidth; cell++ {
+		cellStart := day.Add(time.Duration(cell) * cellDuration)
+		cellEnd := cellStart.Add(cellDuration)
+
+		var covered, best time.Duration
+		bestTag := ""
+		for _, s := range spans {
+			overlapStart := s.start
+			if cellStart.After(overlapStart) {
+				overlapStart = cellStart
+			}
+			overlapEnd := s.end
+			if cellEnd.Before(overlapEnd) {
+				overlapEnd = cellEnd
+			}
+			if d := overlapEnd.Sub(overlapStart); d > 0 {
+				covered += d
+				if d > best {
+					best = d
+					bestTag = s.tag
+				}
+			}
+		}
+
+		switch {
+		case covered > cellDuration:
+			bar.WriteString(overlapStyle.Render("▓"))
+		case covered > 0:
+			color := MutedColor
+			if bestTag != "" {
+				color = getTagColor(bestTag)
+			}
+			bar.WriteString(lipgloss.NewStyle().Foreground(color).Render("█"))
+		case !firstStart.IsZero() && cellStart.Before(lastEnd) && cellEnd.After(firstStart):
+			bar.WriteString(gapStyle.Render("░"))
+		case cellStart.After(now):
+			bar.WriteString(" ")
+		default:
+			bar.WriteString(idleStyle.Render("·"))
+		}
+	}
+	return bar.String(), total, tags
+}
+
+// renderTimelineLegend lists the tag colors and the gap/overlap markers,
+// dropping tags that do not fit in width and cutting off the markers on
+// very narrow panes.
+func renderTimelineLegend(tags []string, width int, gapStyle, overlapStyle lipgloss.Style, getTagColor func(string) lipgloss.Color) string {
+	markers := gapStyle.Render("░") + " gap  " + overlapStyle.Render("▓") + " overlap"
+	used := lipgloss.Width(markers)
+
+	var items []string
+	for _, tag := range tags {
+		item := lipgloss.NewStyle().Foreground(getTagColor(tag)).Render("█") + " #" + tag
+		if used+lipgloss.Width(item)+2 > width {
+			break
+		}
+		items = append(items, item)
+		used += lipgloss.Width(item) + 2
+	}
+	items = append(items, markers)
+	return ansi.Truncate(strings.Join(items, "  "), max(0, width), "")
+}
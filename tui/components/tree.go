@@ -10,11 +10,14 @@ import (
 
 // TagGroup represents entries grouped by tag (from aggregation).
 type TagGroup struct {
-	Tag       string
-	Duration  time.Duration
-	Entries   []storage.Entry
-	Tasks     map[string]time.Duration
-	TaskList  []TaskItem
+	Tag         string
+	Name        string
+	Depth       int
+	HasChildren bool
+	Duration    time.Duration
+	Entries     []storage.Entry
+	Tasks       map[string]time.Duration
+	TaskList    []TaskItem
 }
 
 // TaskItem represents a task with its duration.
@@ -25,55 +28,139 @@ type TaskItem struct {
 	End      time.Time
 }
 
+// VisibleTagGroups returns the groups whose ancestors are all expanded.
+// Groups must be in tree order (parents before children).
+func VisibleTagGroups(groups []TagGroup, collapsed map[string]bool) []TagGroup {
+	var visible []TagGroup
+	for _, group := range groups {
+		hidden := false
+		for _, ancestor := range storage.TagAncestors(group.Tag) {
+			if ancestor != group.Tag && collapsed[ancestor] {
+				hidden = true
+				break
+			}
+		}
+		if !hidden {
+			visible = append(visible, group)
+		}
+	}
+	return visible
+}
+
 // RenderTree renders a hierarchical tree view of entries grouped by tag and task.
-func RenderTree(groups []TagGroup, width, height int, treeTagStyle, treeTaskStyle, treeDurationStyle, boxStyle lipgloss.Style, getTagColor func(string) lipgloss.Color, formatDurationShort func(time.Duration) string) string {
+// Child tags are indented below their parent; collapsed groups hide their
+// children and tasks. The group at index cursor (among visible groups) is
+// highlighted with selectedStyle and kept in view; pass -1 for no cursor.
+func RenderTree(groups []TagGroup, width, height int, collapsed map[string]bool, cursor int, treeTagStyle, treeTaskStyle, treeDurationStyle, selectedStyle, boxStyle lipgloss.Style, getTagColor func(string) lipgloss.Color, formatDurationShort func(time.Duration) string) string {
 	if len(groups) == 0 {
-		return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("No entries in this period."))
+		return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, lipgloss.NewStyle().Foreground(MutedColor).Render("No entries in this period."))
 	}
 
 	var lines []string
 	maxLines := height - 2
-	lineCount := 0
+	cursorLine := 0
+	// Account for box padding (2 chars on each side = 4 total)
+	lineWidth := width - 4
 
-	for _, group := range groups {
-		if lineCount >= maxLines {
-			break
-		}
+	for i, group := range VisibleTagGroups(groups, collapsed) {
+		indent := strings.Repeat("  ", group.Depth)
+		isCollapsed := collapsed[group.Tag]
 
 		// Level 1: Tag with total duration
+		marker := "> "
+		if group.HasChildren || len(group.TaskList) > 0 {
+			if isCollapsed {
+				marker = "▸ "
+			} else {
+				marker = "▾ "
+			}
+		}
 		tagColor := getTagColor(group.Tag)
 		tagStyle := treeTagStyle.Copy().Foreground(tagColor)
-		tagLine := "> " + tagStyle.Render(group.Tag)
-		dots := strings.Repeat(".", max(0, width-len(tagLine)-len(formatDurationShort(group.Duration))-5))
-		tagLine += " " + dots + " " + treeDurationStyle.Render(formatDurationShort(group.Duration))
+		if i == cursor {
+			tagStyle = selectedStyle.Copy().Foreground(tagColor)
+			cursorLine = len(lines)
+		}
+		name := group.Name
+		if name == "" {
+			name = group.Tag
+		}
+		tagLine := indent + marker + tagStyle.Render(name)
+		durationText := formatDurationShort(group.Duration)
+		dots := strings.Repeat(".", max(0, lineWidth-lipgloss.Width(tagLine)-len(durationText)-2))
+		tagLine += " " + dots + " " + treeDurationStyle.Render(durationText)
 		lines = append(lines, tagLine)
-		lineCount++
+
+		if isCollapsed {
+			continue
+		}
 
 		// Level 2: Tasks under this tag
 		for _, task := range group.TaskList {
-			if lineCount >= maxLines {
-				break
-			}
-
 			// Format task with time range
 			timeRange := task.Start.Format("15:04") + " - " + task.End.Format("15:04")
 
-			taskLine := "  - " + treeTaskStyle.Render(task.Text)
-			if len(taskLine)+len(timeRange)+len(formatDurationShort(task.Duration))+10 < width {
+			taskLine := indent + "  - " + treeTaskStyle.Render(task.Text)
+			durationText := formatDurationShort(task.Duration)
+			if lipgloss.Width(taskLine)+len(timeRange)+len(durationText)+10 < lineWidth {
 				taskLine += " (" + timeRange + ")"
 			}
-			dots := strings.Repeat(".", max(0, width-len(taskLine)-len(formatDurationShort(task.Duration))-5))
-			taskLine += " " + dots + " " + treeDurationStyle.Render(formatDurationShort(task.Duration))
+			dots := strings.Repeat(".", max(0, lineWidth-lipgloss.Width(taskLine)-len(durationText)-2))
+			taskLine += " " + dots + " " + treeDurationStyle.Render(durationText)
 
 			lines = append(lines, taskLine)
-			lineCount++
 		}
 	}
 
+	// Scroll so the cursor line stays visible
+	startIdx := treeScrollStart(cursorLine, maxLines)
+	endIdx := min(len(lines), startIdx+max(0, maxLines))
+	lines = lines[startIdx:endIdx]
+
 	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
 	return boxStyle.Width(width).Height(height).Render(content)
 }
 
+// treeScrollStart returns the first line shown so that cursorLine is
+// visible in maxLines lines.
+func treeScrollStart(cursorLine, maxLines int) int {
+	if maxLines > 0 && cursorLine >= maxLines {
+		return cursorLine - maxLines + 1
+	}
+	return 0
+}
+
+// TreeGroupAt returns the index (among visible groups) of the tag shown on
+// content line line of a tree rendered by RenderTree with the same
+// arguments, or -1 if that line is a task or empty.
+func TreeGroupAt(groups []TagGroup, height int, collapsed map[string]bool, cursor, line int) int {
+	// Group index of each line; -1 for task lines
+	var lineGroups []int
+	cursorLine := 0
+	for i, group := range VisibleTagGroups(groups, collapsed) {
+		if i == cursor {
+			cursorLine = len(lineGroups)
+		}
+		lineGroups = append(lineGroups, i)
+		if collapsed[group.Tag] {
+			continue
+		}
+		for range group.TaskList {
+			lineGroups = append(lineGroups, -1)
+		}
+	}
+
+	maxLines := height - 2
+	if line < 0 || line >= maxLines {
+		return -1
+	}
+	line += treeScrollStart(cursorLine, maxLines)
+	if line >= len(lineGroups) {
+		return -1
+	}
+	return lineGroups[line]
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
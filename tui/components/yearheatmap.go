@@ -0,0 +1,116 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// yearHeatmapLabelWidth is the width of the weekday labels before the grid.
+const yearHeatmapLabelWidth = 4
+
+// yearHeatmapMaxWeeks is the number of week columns shown when they fit.
+const yearHeatmapMaxWeeks = 52
+
+// YearHeatmapStart returns the Monday of the first week column shown by a
+// year heatmap of width ending with the week of now.
+func YearHeatmapStart(now time.Time, width int) time.Time {
+	// Account for box padding (2 chars on each side = 4 total)
+	weeks := min(yearHeatmapMaxWeeks, max(1, width-4-yearHeatmapLabelWidth))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	weekday := (int(today.Weekday()) + 6) % 7 // Monday = 0
+	return today.AddDate(0, 0, -weekday-7*(weeks-1))
+}
+
+// yearHeatmapLevel maps a day's total to a color level relative to goal.
+func yearHeatmapLevel(total, goal time.Duration) int {
+	switch {
+	case total <= 0:
+		return 0
+	case goal <= 0 || total >= goal:
+		return 4
+	case total*2 >= goal:
+		return 3
+	case total*4 >= goal:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// RenderYearHeatmap renders a GitHub-style heatmap with a row per weekday
+// and a column per week, ending with the current week. Days are colored by
+// their total (from totals, keyed "2006-01-02") relative to the daily goal.
+// The day at cursor (days before today) is highlighted and described below
+// the legend.
+func RenderYearHeatmap(totals map[string]time.Duration, now time.Time, cursor int, goal time.Duration, width, height int, boxStyle, labelStyle, cursorStyle lipgloss.Style, formatDurationShort func(time.Duration) string) string {
+	start := YearHeatmapStart(now, width)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	weeks := int(today.Sub(start).Hours()/24)/7 + 1
+	selected := today.AddDate(0, 0, -cursor)
+
+	var lines []string
+	lines = append(lines, strings.Repeat(" ", yearHeatmapLabelWidth)+labelStyle.Render(yearHeatmapMonths(start, weeks)))
+
+	dayLabels := []string{"Mon", "", "Wed", "", "Fri", "", ""}
+	for row := 0; row < 7; row++ {
+		var line strings.Builder
+		line.WriteString(labelStyle.Render(fmt.Sprintf("%-*s", yearHeatmapLabelWidth, dayLabels[row])))
+		for week := 0; week < weeks; week++ {
+			day := start.AddDate(0, 0, week*7+row)
+			switch {
+			case day.After(today):
+				line.WriteString(" ")
+			case day.Equal(selected):
+				line.WriteString(cursorStyle.Render("■"))
+			default:
+				level := yearHeatmapLevel(totals[day.Format("2006-01-02")], goal)
+				line.WriteString(lipgloss.NewStyle().Foreground(HeatmapColors[level]).Render("■"))
+			}
+		}
+		lines = append(lines, line.String())
+	}
+
+	// Legend
+	var legend strings.Builder
+	legend.WriteString(labelStyle.Render("Less "))
+	for _, color := range HeatmapColors {
+		legend.WriteString(lipgloss.NewStyle().Foreground(color).Render("■"))
+	}
+	legend.WriteString(labelStyle.Render(" More (goal " + formatDurationShort(goal) + "/day)"))
+	lines = append(lines, "", legend.String())
+
+	// Selected day
+	total := totals[selected.Format("2006-01-02")]
+	summary := selected.Format("Mon 2006-01-02") + "  " + formatDurationShort(total)
+	if goal > 0 {
+		summary += fmt.Sprintf(" (%d%% of goal)", int(100*total/goal))
+	}
+	lines = append(lines, "", summary)
+
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	return boxStyle.Width(width).Height(height).Render(content)
+}
+
+// yearHeatmapMonths renders the month labels above the week columns,
+// each starting at the first week of its month.
+func yearHeatmapMonths(start time.Time, weeks int) string {
+	labels := []rune(strings.Repeat(" ", weeks))
+	free := 0 // First column not covered by the previous label
+	for week := 0; week < weeks; week++ {
+		monday := start.AddDate(0, 0, week*7)
+		// The first column is labeled only if it starts its month
+		if monday.Month() == monday.AddDate(0, 0, -7).Month() {
+			continue
+		}
+		label := monday.Format("Jan")
+		if week < free || week+len(label) > weeks {
+			continue
+		}
+		copy(labels[week:], []rune(label))
+		free = week + len(label) + 1
+	}
+	return string(labels)
+}
@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"time"
+
+	"lazytime/daemon"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// daemonRetryInterval is how often the TUI tries to subscribe while no
+// daemon is running.
+const daemonRetryInterval = 5 * time.Second
+
+// daemonSubscribedMsg carries a subscription to the daemon's change
+// events, nil if no daemon is running.
+type daemonSubscribedMsg struct {
+	sub *daemon.Subscription
+}
+
+// daemonEventMsg reports a change event; ok is false once the daemon
+// went away.
+type daemonEventMsg struct {
+	ok bool
+}
+
+func subscribeDaemonCmd() tea.Cmd {
+	return func() tea.Msg {
+		sub, err := daemon.Subscribe(daemon.SocketPath())
+		if err != nil {
+			return daemonSubscribedMsg{}
+		}
+		return daemonSubscribedMsg{sub: sub}
+	}
+}
+
+func waitDaemonEventCmd(sub *daemon.Subscription) tea.Cmd {
+	return func() tea.Msg {
+		_, ok := <-sub.Events
+		return daemonEventMsg{ok: ok}
+	}
+}
+
+// checkDaemon runs on every tick and subscribes to the daemon once one is
+// running, so changes are shown as soon as it reports them rather than
+// on the next check of the log file.
+func (m *Model) checkDaemon() tea.Cmd {
+	if m.daemonSub != nil || time.Since(m.daemonTriedAt) < daemonRetryInterval {
+		return nil
+	}
+	m.daemonTriedAt = time.Now()
+	return subscribeDaemonCmd()
+}
+
+// handleDaemonMsg handles subscriptions and change events.
+func (m *Model) handleDaemonMsg(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case daemonSubscribedMsg:
+		m.daemonSub = msg.sub
+		if msg.sub == nil {
+			return nil
+		}
+		return waitDaemonEventCmd(msg.sub)
+	case daemonEventMsg:
+		if !msg.ok {
+			m.daemonSub.Close()
+			m.daemonSub = nil
+			return nil
+		}
+		return tea.Batch(loadEntriesCmd(), waitDaemonEventCmd(m.daemonSub))
+	}
+	return nil
+}
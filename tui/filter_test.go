@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rowTexts returns the header text or entry text of each list row.
+func rowTexts(m Model) []string {
+	var texts []string
+	for _, row := range m.listRows() {
+		if row.entryIndex < 0 {
+			texts = append(texts, row.header)
+		} else {
+			texts = append(texts, m.entries[row.entryIndex].Text)
+		}
+	}
+	return texts
+}
+
+func filterModel(t *testing.T) Model {
+	tz := time.FixedZone("UTC-3", -3*3600)
+	at := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2024, month, day, hour, 0, 0, 0, tz)
+	}
+	return testModel(t, at(3, 1, 18),
+		closedEntry(at(2, 29, 9), time.Hour, "Standup #meeting"),
+		closedEntry(at(3, 1, 9), time.Hour, "API #clientA/api"),
+		closedEntry(at(3, 1, 10), time.Hour, "Web #clientA/web"),
+		closedEntry(at(3, 1, 12), time.Hour, "Lunch"),
+	)
+}
+
+func TestFilterPickerNarrowsList(t *testing.T) {
+	m := sendKeys(filterModel(t), "f", "w", "e", "b")
+	if len(m.modalSuggestions) != 1 || m.modalSuggestions[0] != "clienta/web" {
+		t.Fatalf("Expected the picker to offer clienta/web, got %v", m.modalSuggestions)
+	}
+	m = sendKeys(m, "enter")
+	if m.showModal || m.tagFilter != "clienta/web" {
+		t.Fatalf("Expected the filter clienta/web, got %q (showModal=%v)", m.tagFilter, m.showModal)
+	}
+	if got := strings.Join(rowTexts(m), ", "); got != "Web #clientA/web" {
+		t.Errorf("Expected only the web entry, got %q", got)
+	}
+
+	m = sendKeys(m, "F")
+	if m.tagFilter != "" || len(m.listRows()) != 3 {
+		t.Errorf("Expected clearing the filter to list all of today's entries, got %q with %v", m.tagFilter, rowTexts(m))
+	}
+}
+
+func TestFilterIncludesChildTagsAndDropsEmptyHeaders(t *testing.T) {
+	m := filterModel(t)
+	m.setViewMode(ViewWeek)
+	m.setTagFilter("clienta")
+
+	want := "friday, Web #clientA/web, API #clientA/api"
+	if got := strings.Join(rowTexts(m), ", "); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	m.setTagFilter("meeting")
+	want = "thursday, Standup #meeting"
+	if got := strings.Join(rowTexts(m), ", "); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"lazytime/storage"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestGapsUseLocalWorkingHours(t *testing.T) {
+	keys, err := loadKeyMap(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Monday evening two hours east of UTC
+	tz := time.FixedZone("UTC+2", 2*3600)
+	at := func(hour int) time.Time { return time.Date(2024, 1, 1, hour, 0, 0, 0, tz) }
+	end := at(12).UTC()
+	m := Model{
+		keys:             keys,
+		now:              at(18),
+		entries:          []storage.Entry{{Start: at(9).UTC(), End: &end, Text: "Morning"}},
+		activeEntryIndex: -1,
+		selected:         -1,
+		workingHours:     storage.DefaultWorkingHours,
+		pane:             PaneGaps,
+	}
+
+	gaps := m.gaps()
+	if len(gaps) != 1 || !gaps[0].Start.Equal(at(12)) || !gaps[0].End.Equal(at(17)) {
+		t.Fatalf("Expected the gap from 12:00 to 17:00 local, got %v", gaps)
+	}
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(Model)
+	if !m.showModal || m.modalType != "new" || m.modalInput != "@12:00 @17:00 " {
+		t.Errorf("Expected the new entry modal prefilled with local times, got %q %q", m.modalType, m.modalInput)
+	}
+}
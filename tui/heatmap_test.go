@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"lazytime/tui/components"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestYearHeatmapNavigation(t *testing.T) {
+	tz := time.FixedZone("UTC+1", 3600)
+	now := time.Date(2024, 3, 1, 18, 0, 0, 0, tz) // A Friday
+	m := testModel(t, now, closedEntry(time.Date(2024, 2, 21, 9, 0, 0, 0, tz), 4*time.Hour, "Deep work #project"))
+
+	// Up and down move by a day, left and right by a week
+	m = sendKeys(m, "H", "k", "k", "left")
+	if m.pane != PaneHeatmap || m.heatmapCursor != 9 {
+		t.Fatalf("Expected the heatmap cursor 9 days back, got pane %v cursor %d", m.pane, m.heatmapCursor)
+	}
+	view := ansi.Strip(m.View())
+	for _, want := range []string{"Less", "More (goal", "Wed 2024-02-21", "(50% of goal)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the heatmap:\n%s", want, view)
+		}
+	}
+
+	// The cursor stops at today and at the first day shown
+	if m = sendKeys(m, "right", "right"); m.heatmapCursor != 0 {
+		t.Errorf("Expected the cursor to stop at today, got %d", m.heatmapCursor)
+	}
+	for range 60 {
+		m = sendKeys(m, "left")
+	}
+	first := components.YearHeatmapStart(now, m.layout().leftWidth)
+	if day := now.AddDate(0, 0, -m.heatmapCursor); day.Format("2006-01-02") != first.Format("2006-01-02") {
+		t.Errorf("Expected the cursor to stop at the first day %v, got %v", first, day)
+	}
+
+	// Selecting a day opens it in the Today list
+	m.heatmapCursor = 9
+	m = sendKeys(m, "enter")
+	if m.pane != PaneList || m.viewMode != ViewToday || m.rangeOffset != -9 {
+		t.Fatalf("Expected the Today list 9 days back, got pane %v view %v offset %d", m.pane, m.viewMode, m.rangeOffset)
+	}
+	if rows := m.listRows(); len(rows) != 1 || rows[0].entryIndex != 0 {
+		t.Errorf("Expected the day's entry in the list, got %v", rows)
+	}
+}
@@ -0,0 +1,164 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"lazytime/storage"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// idlePollInterval is how often the idle command is run.
+	idlePollInterval = 5 * time.Second
+	// idleCommandTimeout bounds a run of the idle command, so a hanging
+	// command does not pile up behind the next polls.
+	idleCommandTimeout = 2 * time.Second
+)
+
+// idleCheckedMsg carries the desktop idle time reported by the idle
+// command.
+type idleCheckedMsg struct {
+	idle time.Duration
+	err  error
+}
+
+// idleCommandCmd runs the idle command, which prints the idle time in
+// milliseconds (or as a duration like "90s").
+func idleCommandCmd(command string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), idleCommandTimeout)
+		defer cancel()
+		args := strings.Fields(command)
+		out, err := exec.CommandContext(ctx, args[0], args[1:]...).Output()
+		if ctx.Err() == context.DeadlineExceeded {
+			return idleCheckedMsg{err: fmt.Errorf("idle command timed out after %s", idleCommandTimeout)}
+		}
+		if err != nil {
+			return idleCheckedMsg{err: fmt.Errorf("idle command: %w", err)}
+		}
+		idle, err := parseIdleTime(strings.TrimSpace(string(out)))
+		if err != nil {
+			return idleCheckedMsg{err: err}
+		}
+		return idleCheckedMsg{idle: idle}
+	}
+}
+
+// parseIdleTime parses the idle command's output.
+func parseIdleTime(value string) (time.Duration, error) {
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Duration(ms) * time.Millisecond, nil
+	}
+	idle, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("idle command printed %q, expected milliseconds", value)
+	}
+	return idle, nil
+}
+
+// checkIdle runs on every tick. With an idle command it polls the desktop
+// idle time; otherwise the time since the last key press in the TUI
+// counts, and the idle prompt opens on the next key press.
+func (m *Model) checkIdle() tea.Cmd {
+	if m.idleTimeout == 0 || m.activeEntryIndex == -1 || m.showModal {
+		return nil
+	}
+	if m.idleCommand != "" {
+		if m.now.Sub(m.idleCheckedAt) < idlePollInterval {
+			return nil
+		}
+		m.idleCheckedAt = m.now
+		return idleCommandCmd(m.idleCommand)
+	}
+	if m.idleStart.IsZero() && m.now.Sub(m.lastActivity) >= m.idleTimeout {
+		m.idleStart = m.lastActivity
+	}
+	return nil
+}
+
+// handleIdleChecked tracks the idle time reported by the idle command and
+// opens the idle prompt once input resumes after an idle period.
+func (m *Model) handleIdleChecked(msg idleCheckedMsg) {
+	if msg.err != nil {
+		m.setMessage("Error: "+msg.err.Error(), true)
+		return
+	}
+	lastInput := m.now.Add(-msg.idle)
+	if msg.idle >= m.idleTimeout {
+		if m.idleStart.IsZero() {
+			m.idleStart = lastInput
+		}
+		return
+	}
+	if !m.idleStart.IsZero() {
+		m.openIdleModal(lastInput)
+	}
+}
+
+// recordActivity notes input in the TUI. Returns true if it ends an idle
+// period, in which case the idle prompt was opened and the input should be
+// ignored.
+func (m *Model) recordActivity() bool {
+	now := storage.UTCNow()
+	m.lastActivity = now
+	if m.idleCommand != "" || m.idleStart.IsZero() || m.showModal {
+		return false
+	}
+	return m.openIdleModal(now)
+}
+
+// openIdleModal asks what to do with the idle time from idleStart to end
+// in the running entry. Idle time before the entry started is not counted;
+// nothing is asked if what is left is shorter than the idle timeout or no
+// entry is running. Returns true if the prompt was opened.
+func (m *Model) openIdleModal(end time.Time) bool {
+	start := m.idleStart
+	m.idleStart = time.Time{}
+	if m.activeEntryIndex == -1 || m.showModal {
+		return false
+	}
+	entry := m.entries[m.activeEntryIndex]
+	if start.Before(entry.Start) {
+		start = entry.Start
+	}
+	if end.Sub(start) < m.idleTimeout {
+		return false
+	}
+
+	m.openTextModal("idle", "", entry)
+	m.idleFrom, m.idleTo = start, end
+	m.modalPrompt = m.idlePrompt()
+	return true
+}
+
+// idlePrompt describes the idle period of the idle modal.
+func (m Model) idlePrompt() string {
+	tz := m.now.Location()
+	return fmt.Sprintf("You were idle from %s to %s (%s) while \"%s\" was running.",
+		m.idleFrom.In(tz).Format("15:04"), m.idleTo.In(tz).Format("15:04"), FormatDurationShort(m.idleTo.Sub(m.idleFrom)), m.modalTarget.Text)
+}
+
+// splitIdleCmd cuts the idle period out of the target entry, logging it
+// with text or discarding it if text is empty.
+func splitIdleCmd(target storage.Entry, start, end time.Time, text string) tea.Cmd {
+	return rewriteEntryCmd(target, func(entries []storage.Entry, idx int) ([]storage.Entry, string, error) {
+		parts, err := storage.SplitIdle(entries[idx], start, end, text)
+		if err != nil {
+			return nil, "", err
+		}
+		updated := make([]storage.Entry, 0, len(entries)+2)
+		updated = append(updated, entries[:idx]...)
+		updated = append(updated, parts...)
+		updated = append(updated, entries[idx+1:]...)
+		if text == "" {
+			return updated, "Discarded " + FormatDurationShort(end.Sub(start)) + " idle time", nil
+		}
+		return updated, "Reassigned " + FormatDurationShort(end.Sub(start)) + " to: " + text, nil
+	})
+}
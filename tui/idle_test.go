@@ -0,0 +1,139 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"lazytime/storage"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// idleModel returns a model with an entry running since start and a
+// 10 minute idle timeout.
+func idleModel(t *testing.T, start time.Time) Model {
+	t.Helper()
+	keys, err := loadKeyMap(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Model{
+		keys:             keys,
+		now:              storage.UTCNow(),
+		entries:          []storage.Entry{{Start: start, Text: "Coding #dev"}},
+		activeEntryIndex: 0,
+		idleTimeout:      10 * time.Minute,
+	}
+}
+
+func TestOpenIdleModal(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		entry     time.Time
+		idleStart time.Time
+		wantOpen  bool
+		wantFrom  time.Time
+	}{
+		{"idle period", now.Add(-2 * time.Hour), now.Add(-time.Hour), true, now.Add(-time.Hour)},
+		{"clamped to the entry start", now.Add(-30 * time.Minute), now.Add(-time.Hour), true, now.Add(-30 * time.Minute)},
+		{"shorter than the timeout", now.Add(-2 * time.Hour), now.Add(-5 * time.Minute), false, time.Time{}},
+		{"shorter than the timeout after clamping", now.Add(-5 * time.Minute), now.Add(-time.Hour), false, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := idleModel(t, tt.entry)
+			m.idleStart = tt.idleStart
+			if got := m.openIdleModal(now); got != tt.wantOpen {
+				t.Fatalf("openIdleModal() = %v, want %v", got, tt.wantOpen)
+			}
+			if !m.idleStart.IsZero() {
+				t.Errorf("Expected the idle period to be reset, got %v", m.idleStart)
+			}
+			if !tt.wantOpen {
+				if m.showModal {
+					t.Errorf("Expected no modal, got %q", m.modalType)
+				}
+				return
+			}
+			if m.modalType != "idle" || !m.idleFrom.Equal(tt.wantFrom) || !m.idleTo.Equal(now) {
+				t.Errorf("Expected the idle modal for %v to %v, got %q for %v to %v", tt.wantFrom, now, m.modalType, m.idleFrom, m.idleTo)
+			}
+		})
+	}
+}
+
+func TestIdleKeyPressIsSwallowed(t *testing.T) {
+	m := idleModel(t, storage.UTCNow().Add(-2*time.Hour))
+	m.idleStart = storage.UTCNow().Add(-time.Hour)
+
+	// The key press ending the idle period only opens the prompt
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m = next.(Model)
+	if cmd != nil {
+		t.Error("Expected the key press not to run its binding")
+	}
+	if !m.showModal || m.modalType != "idle" {
+		t.Fatalf("Expected the idle modal, got showModal=%v modalType=%q", m.showModal, m.modalType)
+	}
+
+	// Later key presses reach the prompt
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	if m = next.(Model); m.showModal {
+		t.Errorf("Expected keep to close the idle modal, got %q", m.modalType)
+	}
+}
+
+func TestIdleCommandPath(t *testing.T) {
+	m := idleModel(t, storage.UTCNow().Add(-2*time.Hour))
+	m.idleCommand = "echo 900000"
+
+	cmd := m.checkIdle()
+	if cmd == nil {
+		t.Fatal("Expected checkIdle to run the idle command")
+	}
+	if again := m.checkIdle(); again != nil {
+		t.Error("Expected the idle command not to run again before the poll interval")
+	}
+	msg, ok := cmd().(idleCheckedMsg)
+	if !ok || msg.err != nil || msg.idle != 15*time.Minute {
+		t.Fatalf("Expected 15m of idle time, got %+v", msg)
+	}
+
+	// Idle past the timeout marks the start of the idle period
+	m.handleIdleChecked(msg)
+	wantStart := m.now.Add(-15 * time.Minute)
+	if !m.idleStart.Equal(wantStart) || m.showModal {
+		t.Fatalf("Expected the idle period to start at %v without a prompt, got %v", wantStart, m.idleStart)
+	}
+
+	// Key presses in the TUI do not end the idle period of the idle command
+	if m.recordActivity() {
+		t.Error("Expected TUI input to be ignored with an idle command")
+	}
+
+	// Input resuming on the desktop opens the prompt
+	m.now = m.now.Add(time.Minute)
+	m.handleIdleChecked(idleCheckedMsg{idle: time.Second})
+	if !m.showModal || m.modalType != "idle" || !m.idleFrom.Equal(wantStart) {
+		t.Errorf("Expected the idle modal from %v, got showModal=%v from %v", wantStart, m.showModal, m.idleFrom)
+	}
+}
+
+func TestParseIdleTime(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"1500", 1500 * time.Millisecond, false},
+		{"90s", 90 * time.Second, false},
+		{"soon", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseIdleTime(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseIdleTime(%q) = %v, %v; want %v, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,368 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"lazytime/tui/components"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Binding is an action bound to one or more keys, named by the config key
+// used to rebind it.
+type Binding struct {
+	Name string   // Config name, e.g. "new_entry"
+	Keys []string // Key names as reported by tea.KeyMsg.String()
+	Desc string   // Help text
+}
+
+// Matches reports whether msg is one of the binding's keys.
+func (b Binding) Matches(msg tea.KeyMsg) bool {
+	key := msg.String()
+	for _, k := range b.Keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// HelpKeys renders the binding's keys for the help modal and the footer.
+func (b Binding) HelpKeys() string {
+	labels := make([]string, len(b.Keys))
+	for i, k := range b.Keys {
+		labels[i] = keyLabel(k)
+	}
+	return strings.Join(labels, "/")
+}
+
+// keyLabel returns the display name of a key.
+func keyLabel(key string) string {
+	switch key {
+	case "enter":
+		return "↵"
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	case " ":
+		return "Space"
+	case "esc":
+		return "Esc"
+	case "tab":
+		return "Tab"
+	case "shift+tab":
+		return "Shift+Tab"
+	}
+	// ctrl+w is shown as Ctrl+W
+	for _, modifier := range []string{"ctrl+", "alt+"} {
+		if rest, ok := strings.CutPrefix(key, modifier); ok && key != modifier {
+			return strings.ToUpper(modifier[:1]) + modifier[1:] + keyLabel(strings.ToUpper(rest))
+		}
+	}
+	return key
+}
+
+// KeyMap holds the key bindings of the TUI. Global bindings are handled in
+// the main view; pane bindings by the tag tree, gaps and heatmap panes
+// before the global ones; modal bindings inside modals, where all other
+// keys edit the text input.
+type KeyMap struct {
+	// Global
+	Quit        Binding
+	Help        Binding
+	ViewToday   Binding
+	ViewWeek    Binding
+	ViewMonth   Binding
+	ViewYear    Binding
+	PrevRange   Binding
+	NextRange   Binding
+	Tree        Binding
+	Timeline    Binding
+	Gaps        Binding
+	Heatmap     Binding
+	Stats       Binding
+	Sidebar     Binding
+	New         Binding
+	Search      Binding
+	Filter      Binding
+	ClearFilter Binding
+	Edit        Binding
+	Duplicate   Binding
+	Resume      Binding
+	Split       Binding
+	Delete      Binding
+	Stop        Binding
+	Reload      Binding
+
+	// Lists and panes
+	Up            Binding
+	Down          Binding
+	Left          Binding
+	Right         Binding
+	Select        Binding
+	ToggleNode    Binding
+	CollapseLevel Binding
+	ExpandLevel   Binding
+
+	// Modals
+	Confirm   Binding
+	Cancel    Binding
+	Complete  Binding
+	PrevField Binding
+	Yes       Binding
+	No        Binding
+
+	// Idle prompt
+	KeepIdle     Binding
+	DiscardIdle  Binding
+	ReassignIdle Binding
+}
+
+// defaultKeyMap returns the default key bindings.
+func defaultKeyMap() KeyMap {
+	return KeyMap{
+		Quit:        Binding{"quit", []string{"q", "esc", "ctrl+c"}, "Quit"},
+		Help:        Binding{"help", []string{"?"}, "Show this help"},
+		ViewToday:   Binding{"view_today", []string{"1"}, "Today view"},
+		ViewWeek:    Binding{"view_week", []string{"2"}, "Week view"},
+		ViewMonth:   Binding{"view_month", []string{"3"}, "Month view"},
+		ViewYear:    Binding{"view_year", []string{"4"}, "Year view"},
+		PrevRange:   Binding{"prev_range", []string{"["}, "Previous day, week, month or year"},
+		NextRange:   Binding{"next_range", []string{"]"}, "Next day, week, month or year"},
+		Tree:        Binding{"tree", []string{"t"}, "Toggle tag tree"},
+		Timeline:    Binding{"timeline", []string{"T"}, "Toggle timeline (24h bar per day)"},
+		Gaps:        Binding{"gaps", []string{"g"}, "Toggle gaps (select logs the gap)"},
+		Heatmap:     Binding{"heatmap", []string{"H"}, "Toggle 52-week heatmap (select opens the day)"},
+		Stats:       Binding{"stats", []string{"c"}, "Toggle stats charts"},
+		Sidebar:     Binding{"sidebar", []string{"b"}, "Toggle sidebar (goals and heatmap)"},
+		New:         Binding{"new_entry", []string{"n"}, "Start new entry (Tab completes suggestions)"},
+		Search:      Binding{"search", []string{"/"}, "Search all entries (select jumps to the day)"},
+		Filter:      Binding{"filter", []string{"f"}, "Filter views by tag"},
+		ClearFilter: Binding{"clear_filter", []string{"F"}, "Clear the tag filter"},
+		Edit:        Binding{"edit", []string{"enter", "e"}, "Edit selected entry"},
+		Duplicate:   Binding{"duplicate", []string{"y"}, "Duplicate selected entry"},
+		Resume:      Binding{"resume", []string{"s"}, "Resume selected entry"},
+		Split:       Binding{"split", []string{"S"}, "Split selected entry"},
+		Delete:      Binding{"delete", []string{"d"}, "Delete selected entry"},
+		Stop:        Binding{"stop", []string{"x"}, "Stop current entry"},
+		Reload:      Binding{"reload", []string{"r"}, "Reload log file (also automatic on change)"},
+
+		Up:            Binding{"up", []string{"up", "k"}, "Move up / earlier day"},
+		Down:          Binding{"down", []string{"down", "j"}, "Move down / later day"},
+		Left:          Binding{"left", []string{"left", "h"}, "Collapse tag / previous week"},
+		Right:         Binding{"right", []string{"right", "l"}, "Expand tag / next week"},
+		Select:        Binding{"select", []string{"enter"}, "Toggle tag, log gap, open heatmap day"},
+		ToggleNode:    Binding{"toggle_node", []string{" "}, "Expand or collapse tag"},
+		CollapseLevel: Binding{"collapse_level", []string{"-"}, "Collapse all tags at this level"},
+		ExpandLevel:   Binding{"expand_level", []string{"+", "="}, "Expand all tags at this level"},
+
+		Confirm:   Binding{"confirm", []string{"enter"}, "Confirm"},
+		Cancel:    Binding{"cancel", []string{"esc"}, "Cancel / close"},
+		Complete:  Binding{"complete", []string{"tab"}, "Complete suggestion / next field"},
+		PrevField: Binding{"prev_field", []string{"shift+tab"}, "Previous field"},
+		Yes:       Binding{"yes", []string{"y", "Y"}, "Answer yes"},
+		No:        Binding{"no", []string{"n", "N"}, "Answer no"},
+
+		KeepIdle:     Binding{"keep_idle", []string{"k"}, "Keep idle time in the running entry"},
+		DiscardIdle:  Binding{"discard_idle", []string{"d"}, "Discard idle time"},
+		ReassignIdle: Binding{"reassign_idle", []string{"r"}, "Log idle time as another entry"},
+	}
+}
+
+// global returns the bindings of the main view, in help order.
+func (k *KeyMap) global() []*Binding {
+	return []*Binding{
+		&k.ViewToday, &k.ViewWeek, &k.ViewMonth, &k.ViewYear, &k.PrevRange, &k.NextRange,
+		&k.Tree, &k.Timeline, &k.Gaps, &k.Heatmap, &k.Stats, &k.Sidebar,
+		&k.New, &k.Search, &k.Filter, &k.ClearFilter, &k.Edit, &k.Duplicate, &k.Resume, &k.Split, &k.Delete, &k.Stop, &k.Reload,
+		&k.Help, &k.Quit,
+	}
+}
+
+// panes returns the bindings of lists and panes, in help order.
+func (k *KeyMap) panes() []*Binding {
+	return []*Binding{&k.Up, &k.Down, &k.Left, &k.Right, &k.Select, &k.ToggleNode, &k.CollapseLevel, &k.ExpandLevel}
+}
+
+// modals returns the bindings of modals, in help order.
+func (k *KeyMap) modals() []*Binding {
+	return []*Binding{&k.Confirm, &k.Cancel, &k.Complete, &k.PrevField, &k.Yes, &k.No, &k.KeepIdle, &k.DiscardIdle, &k.ReassignIdle}
+}
+
+// loadKeyMap returns the default key bindings with the keys of the actions
+// in overrides (by binding name) replaced. "space" stands for the space
+// key. Two actions of the same group may not share a key, and neither may
+// a global and a pane action.
+func loadKeyMap(overrides map[string][]string) (KeyMap, error) {
+	keys := defaultKeyMap()
+	byName := make(map[string]*Binding)
+	groups := [][]*Binding{keys.global(), keys.panes(), keys.modals()}
+	for _, group := range groups {
+		for _, binding := range group {
+			byName[binding.Name] = binding
+		}
+	}
+
+	// Sorted for deterministic errors
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		binding, ok := byName[name]
+		if !ok {
+			return defaultKeyMap(), fmt.Errorf("unknown key binding action %q", name)
+		}
+		if len(overrides[name]) == 0 {
+			return defaultKeyMap(), fmt.Errorf("key binding %q has no keys", name)
+		}
+		binding.Keys = nil
+		for _, key := range overrides[name] {
+			if key == "space" {
+				key = " "
+			}
+			binding.Keys = append(binding.Keys, key)
+		}
+	}
+
+	for _, group := range groups {
+		owner := make(map[string]string)
+		for _, binding := range group {
+			for _, key := range binding.Keys {
+				if other, taken := owner[key]; taken {
+					return defaultKeyMap(), fmt.Errorf("key %q is bound to both %s and %s", keyLabel(key), other, binding.Name)
+				}
+				owner[key] = binding.Name
+			}
+		}
+	}
+
+	// Pane keys are handled before global ones, so a global action sharing
+	// a key with a pane action would stop working while that pane is shown.
+	// Edit and Select share enter on purpose: Edit acts on the entry list,
+	// Select on the other panes.
+	paneOwner := make(map[string]*Binding)
+	for _, binding := range keys.panes() {
+		for _, key := range binding.Keys {
+			paneOwner[key] = binding
+		}
+	}
+	for _, binding := range keys.global() {
+		for _, key := range binding.Keys {
+			pane, taken := paneOwner[key]
+			if taken && !(binding == &keys.Edit && pane == &keys.Select) {
+				return defaultKeyMap(), fmt.Errorf("key %q is bound to both %s and %s", keyLabel(key), binding.Name, pane.Name)
+			}
+		}
+	}
+	return keys, nil
+}
+
+// helpSections lists the active key bindings for the help modal, followed
+// by the fixed keys of text inputs and the entry syntax.
+func (k *KeyMap) helpSections() []components.HelpSection {
+	section := func(title string, bindings []*Binding) components.HelpSection {
+		s := components.HelpSection{Title: title}
+		for _, binding := range bindings {
+			s.Items = append(s.Items, components.HelpItem{Keys: binding.HelpKeys(), Desc: binding.Desc})
+		}
+		return s
+	}
+	return []components.HelpSection{
+		section("Keys", k.global()),
+		section("Lists and Panes", k.panes()),
+		section("Modals", k.modals()),
+		{Title: "Text Input", Items: []components.HelpItem{
+			{Keys: "←/→", Desc: "Move the cursor"},
+			{Keys: "Ctrl+←/→", Desc: "Move by word (also Alt+B/Alt+F)"},
+			{Keys: "Home/End", Desc: "Go to either end (also Ctrl+A/Ctrl+E)"},
+			{Keys: "Ctrl+W/U/K", Desc: "Delete word / to start / to end"},
+			{Keys: "↑/↓", Desc: "Pick a suggestion, or browse earlier inputs"},
+		}},
+		{Title: "Mouse", Lines: []string{
+			"Click tabs, entries, tree tags (filter) and heatmap days",
+			"The wheel scrolls the main pane",
+		}},
+		{Title: "Time Overrides", Items: []components.HelpItem{
+			{Keys: "@HH:MM", Desc: "Backdate start time for today"},
+			{Keys: "@HH:MM @HH:MM", Desc: "Add completed entry"},
+			{Keys: "@YYYY-MM-DDTHH:MM", Desc: "Same for another day"},
+		}, Lines: []string{
+			`Example: "Task @09:00" or "Task @09:00 @10:30"`,
+		}},
+		{Title: "Tags & Labels", Lines: []string{
+			"Tags are words starting with # in entry text",
+			`Example: "Write docs #project #writing"`,
+			"Multiple tags allowed per entry",
+		}},
+	}
+}
+
+// helpHint lists the keys that scroll and close the help modal.
+func (k *KeyMap) helpHint() string {
+	return keyLabel(k.Up.Keys[0]) + "/" + keyLabel(k.Down.Keys[0]) + ": Scroll  " +
+		keyLabel(k.Help.Keys[0]) + "/" + keyLabel(k.Cancel.Keys[0]) + ": Close"
+}
+
+// modalHint returns the key hint at the bottom of a modal, listing the
+// first key of each action. The arrow keys of modals are fixed.
+func (k *KeyMap) modalHint(modalType string, hasSuggestions bool) string {
+	first := func(b Binding) string { return keyLabel(b.Keys[0]) }
+	switch {
+	case modalType == "confirm":
+		return first(k.Yes) + "/" + first(k.Confirm) + ": Yes  " + first(k.No) + "/" + first(k.Cancel) + ": No"
+	case modalType == "idle":
+		return first(k.KeepIdle) + "/" + first(k.Cancel) + ": Keep  " + first(k.DiscardIdle) + ": Discard  " + first(k.ReassignIdle) + ": Reassign"
+	case modalType == "search":
+		return "↑/↓: Select  " + first(k.Confirm) + ": Jump to day  " + first(k.Cancel) + ": Cancel"
+	case modalType == "edit" && hasSuggestions:
+		return first(k.Complete) + ": Complete  " + first(k.Confirm) + ": Save  " + first(k.Cancel) + ": Cancel"
+	case modalType == "edit":
+		return first(k.Complete) + ": Next field  " + first(k.Confirm) + ": Save  " + first(k.Cancel) + ": Cancel"
+	case hasSuggestions && modalType != "filter":
+		return first(k.Complete) + ": Complete  ↑/↓: Select  " + first(k.Confirm) + ": Confirm  " + first(k.Cancel) + ": Cancel"
+	}
+	return first(k.Confirm) + ": Confirm  " + first(k.Cancel) + ": Cancel"
+}
+
+// footerItems lists the bindings shown in the footer, in order.
+func (k *KeyMap) footerItems() []components.HelpItem {
+	join := func(bindings ...*Binding) string {
+		// Consecutive digits are shown as a range, like 1-4
+		consecutive := len(bindings) > 2
+		for i, binding := range bindings {
+			key := binding.Keys[0]
+			if len(key) != 1 || key[0] != bindings[0].Keys[0][0]+byte(i) || key[0] < '0' || key[0] > '9' {
+				consecutive = false
+			}
+		}
+		if consecutive {
+			return bindings[0].Keys[0] + "-" + bindings[len(bindings)-1].Keys[0]
+		}
+		labels := make([]string, len(bindings))
+		for i, binding := range bindings {
+			labels[i] = keyLabel(binding.Keys[0])
+		}
+		return strings.Join(labels, "/")
+	}
+	return []components.HelpItem{
+		{Keys: join(&k.ViewToday, &k.ViewWeek, &k.ViewMonth, &k.ViewYear), Desc: "Views"},
+		{Keys: join(&k.PrevRange, &k.NextRange), Desc: "Range"},
+		{Keys: join(&k.Tree, &k.Timeline, &k.Gaps, &k.Heatmap, &k.Stats), Desc: "Panes"},
+		{Keys: join(&k.New), Desc: "New"},
+		{Keys: join(&k.Search), Desc: "Search"},
+		{Keys: join(&k.Edit), Desc: "Edit"},
+		{Keys: join(&k.Delete), Desc: "Del"},
+		{Keys: join(&k.Stop), Desc: "Stop"},
+		{Keys: join(&k.Help), Desc: "Help"},
+		{Keys: join(&k.Quit), Desc: "Quit"},
+	}
+}
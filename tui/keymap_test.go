@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadKeyMapConflicts(t *testing.T) {
+	if _, err := loadKeyMap(nil); err != nil {
+		t.Fatalf("Expected the defaults to load, got %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		overrides map[string][]string
+		wantErr   string
+	}{
+		{"unknown action", map[string][]string{"teleport": {"z"}}, "unknown key binding action"},
+		{"same group", map[string][]string{"tree": {"n"}}, `"n" is bound to both`},
+		{"global and pane", map[string][]string{"tree": {"k"}}, `"k" is bound to both tree and up`},
+		{"pane and global", map[string][]string{"toggle_node": {"x"}}, `"x" is bound to both stop and toggle_node`},
+		{"edit and select share a key", map[string][]string{"edit": {"o"}, "select": {"o"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadKeyMap(tt.overrides)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestModalHintFollowsBindings(t *testing.T) {
+	keys, err := loadKeyMap(map[string][]string{"yes": {"o"}, "cancel": {"ctrl+g"}, "complete": {"ctrl+n"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		modalType      string
+		hasSuggestions bool
+		want           string
+	}{
+		{"confirm", false, "o/↵: Yes  n/Ctrl+G: No"},
+		{"new", true, "Ctrl+N: Complete  ↑/↓: Select  ↵: Confirm  Ctrl+G: Cancel"},
+		{"filter", true, "↵: Confirm  Ctrl+G: Cancel"},
+		{"edit", false, "Ctrl+N: Next field  ↵: Save  Ctrl+G: Cancel"},
+		{"search", false, "↑/↓: Select  ↵: Jump to day  Ctrl+G: Cancel"},
+		{"idle", false, "k/Ctrl+G: Keep  d: Discard  r: Reassign"},
+	}
+	for _, tt := range tests {
+		if got := keys.modalHint(tt.modalType, tt.hasSuggestions); got != tt.want {
+			t.Errorf("modalHint(%q, %v) = %q, want %q", tt.modalType, tt.hasSuggestions, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package tui
+
+import (
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// inputHistoryLimit is the number of inputs remembered per modal type.
+const inputHistoryLimit = 50
+
+// pasteReplacer turns line breaks and tabs in pasted text into spaces.
+var pasteReplacer = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\t", " ")
+
+// editLine applies an editing key to value with the cursor at rune index
+// cursor. It returns the new value and cursor, and whether the key was an
+// editing key.
+func editLine(value string, cursor int, msg tea.KeyMsg) (string, int, bool) {
+	runes := []rune(value)
+	cursor = min(max(0, cursor), len(runes))
+
+	switch msg.Type {
+	case tea.KeyRunes:
+		if msg.Alt {
+			break // alt+letter word commands below
+		}
+		inserted := msg.Runes
+		if msg.Paste {
+			// Pasted text stays on one line
+			inserted = []rune(pasteReplacer.Replace(string(msg.Runes)))
+		}
+		return insertRunes(runes, cursor, inserted)
+	case tea.KeySpace:
+		return insertRunes(runes, cursor, []rune{' '})
+	}
+
+	switch msg.String() {
+	case "left", "ctrl+b":
+		return value, max(0, cursor-1), true
+	case "right", "ctrl+f":
+		return value, min(len(runes), cursor+1), true
+	case "ctrl+left", "alt+left", "alt+b":
+		return value, wordStart(runes, cursor), true
+	case "ctrl+right", "alt+right", "alt+f":
+		return value, wordEnd(runes, cursor), true
+	case "home", "ctrl+a":
+		return value, 0, true
+	case "end", "ctrl+e":
+		return value, len(runes), true
+	case "backspace", "ctrl+h":
+		if cursor == 0 {
+			return value, cursor, true
+		}
+		return string(runes[:cursor-1]) + string(runes[cursor:]), cursor - 1, true
+	case "delete", "ctrl+d":
+		if cursor == len(runes) {
+			return value, cursor, true
+		}
+		return string(runes[:cursor]) + string(runes[cursor+1:]), cursor, true
+	case "ctrl+w", "alt+backspace":
+		start := wordStart(runes, cursor)
+		return string(runes[:start]) + string(runes[cursor:]), start, true
+	case "alt+d":
+		end := wordEnd(runes, cursor)
+		return string(runes[:cursor]) + string(runes[end:]), cursor, true
+	case "ctrl+u":
+		return string(runes[cursor:]), 0, true
+	case "ctrl+k":
+		return string(runes[:cursor]), cursor, true
+	}
+	return value, cursor, false
+}
+
+// insertRunes inserts inserted into runes at cursor.
+func insertRunes(runes []rune, cursor int, inserted []rune) (string, int, bool) {
+	result := make([]rune, 0, len(runes)+len(inserted))
+	result = append(result, runes[:cursor]...)
+	result = append(result, inserted...)
+	result = append(result, runes[cursor:]...)
+	return string(result), cursor + len(inserted), true
+}
+
+// wordStart returns the index of the start of the word before cursor,
+// skipping any spaces directly before it.
+func wordStart(runes []rune, cursor int) int {
+	i := cursor
+	for i > 0 && unicode.IsSpace(runes[i-1]) {
+		i--
+	}
+	for i > 0 && !unicode.IsSpace(runes[i-1]) {
+		i--
+	}
+	return i
+}
+
+// wordEnd returns the index of the end of the word after cursor, skipping
+// any spaces directly after it.
+func wordEnd(runes []rune, cursor int) int {
+	i := cursor
+	for i < len(runes) && unicode.IsSpace(runes[i]) {
+		i++
+	}
+	for i < len(runes) && !unicode.IsSpace(runes[i]) {
+		i++
+	}
+	return i
+}
+
+// recordHistory remembers a submitted input for the modal type, most
+// recent last and without repeats.
+func (m *Model) recordHistory(modalType, input string) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return
+	}
+	var history []string
+	for _, previous := range m.inputHistory[modalType] {
+		if previous != input {
+			history = append(history, previous)
+		}
+	}
+	history = append(history, input)
+	if len(history) > inputHistoryLimit {
+		history = history[len(history)-inputHistoryLimit:]
+	}
+	m.inputHistory[modalType] = history
+}
+
+// browseHistory replaces the modal input with an older (step -1) or newer
+// (step 1) input of the same modal type. Moving past the newest input
+// restores what was being typed.
+func (m *Model) browseHistory(step int) {
+	history := m.inputHistory[m.modalType]
+	if len(history) == 0 {
+		return
+	}
+	index := m.historyIndex
+	if index == -1 {
+		if step > 0 {
+			return
+		}
+		m.historyDraft = m.modalInput
+		index = len(history)
+	}
+	index += step
+	switch {
+	case index < 0:
+		return
+	case index >= len(history):
+		m.historyIndex = -1
+		m.setActiveInput(m.historyDraft)
+	default:
+		m.historyIndex = index
+		m.setActiveInput(history[index])
+	}
+	// Suggestions would take over up/down; they return on the next edit
+	m.modalSuggestions = []string{}
+	m.modalSelected = 0
+	if m.modalType == "search" {
+		m.searchMatches = searchEntries(m.entries, m.modalInput)
+	}
+}
@@ -0,0 +1,124 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestEditLine(t *testing.T) {
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	key := func(keyType tea.KeyType) tea.KeyMsg { return tea.KeyMsg{Type: keyType} }
+	alt := func(msg tea.KeyMsg) tea.KeyMsg { msg.Alt = true; return msg }
+
+	tests := []struct {
+		name       string
+		value      string
+		cursor     int
+		msg        tea.KeyMsg
+		wantValue  string
+		wantCursor int
+		wantOK     bool
+	}{
+		{"insert at end", "ab", 2, runes("c"), "abc", 3, true},
+		{"insert in middle", "ac", 1, runes("b"), "abc", 2, true},
+		{"insert multi-byte", "née", 1, runes("ü"), "nüée", 2, true},
+		{"insert space", "ab", 1, key(tea.KeySpace), "a b", 2, true},
+		{"paste joins lines", "", 0, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a\r\nb\tc"), Paste: true}, "a b c", 5, true},
+		{"cursor clamped", "ab", 9, runes("c"), "abc", 3, true},
+		{"left at start", "ab", 0, key(tea.KeyLeft), "ab", 0, true},
+		{"right at end", "ab", 2, key(tea.KeyRight), "ab", 2, true},
+		{"right over multi-byte", "éa", 0, key(tea.KeyRight), "éa", 1, true},
+		{"home", "abc", 2, key(tea.KeyHome), "abc", 0, true},
+		{"end counts runes", "日本語", 0, key(tea.KeyEnd), "日本語", 3, true},
+		{"backspace at start", "ab", 0, key(tea.KeyBackspace), "ab", 0, true},
+		{"backspace multi-byte", "aéb", 2, key(tea.KeyBackspace), "ab", 1, true},
+		{"delete at end", "ab", 2, key(tea.KeyDelete), "ab", 2, true},
+		{"delete under cursor", "abc", 1, key(tea.KeyDelete), "ac", 1, true},
+		{"ctrl+w deletes word", "fix bug #api", 12, key(tea.KeyCtrlW), "fix bug ", 8, true},
+		{"ctrl+w skips spaces", "fix bug   ", 10, key(tea.KeyCtrlW), "fix ", 4, true},
+		{"ctrl+w at start", "fix", 0, key(tea.KeyCtrlW), "fix", 0, true},
+		{"alt+d deletes next word", "fix  bug now", 3, alt(runes("d")), "fix now", 3, true},
+		{"ctrl+u", "fix bug", 4, key(tea.KeyCtrlU), "bug", 0, true},
+		{"ctrl+k", "fix bug", 4, key(tea.KeyCtrlK), "fix ", 4, true},
+		{"alt+b jumps word", "fix bug", 7, alt(runes("b")), "fix bug", 4, true},
+		{"alt+f jumps word", "fix bug", 0, alt(runes("f")), "fix bug", 3, true},
+		{"other alt letter", "fix", 1, alt(runes("x")), "fix", 1, false},
+		{"not an editing key", "fix", 1, key(tea.KeyEnter), "fix", 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, cursor, ok := editLine(tt.value, tt.cursor, tt.msg)
+			if value != tt.wantValue || cursor != tt.wantCursor || ok != tt.wantOK {
+				t.Errorf("editLine(%q, %d, %s) = %q, %d, %v; want %q, %d, %v",
+					tt.value, tt.cursor, tt.msg, value, cursor, ok, tt.wantValue, tt.wantCursor, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestWordStartAndEnd(t *testing.T) {
+	tests := []struct {
+		value     string
+		cursor    int
+		wantStart int
+		wantEnd   int
+	}{
+		{"", 0, 0, 0},
+		{"word", 0, 0, 4},
+		{"word", 4, 0, 4},
+		{"word", 2, 0, 4},
+		{"one two", 3, 0, 7},
+		{"one two", 4, 0, 7},
+		{"one   two", 6, 0, 9},
+		{"  lead", 2, 0, 6},
+		{"trail  ", 5, 0, 7},
+		{"über straße", 5, 0, 11},
+		{"über straße", 11, 5, 11},
+	}
+	for _, tt := range tests {
+		runes := []rune(tt.value)
+		if got := wordStart(runes, tt.cursor); got != tt.wantStart {
+			t.Errorf("wordStart(%q, %d) = %d, want %d", tt.value, tt.cursor, got, tt.wantStart)
+		}
+		if got := wordEnd(runes, tt.cursor); got != tt.wantEnd {
+			t.Errorf("wordEnd(%q, %d) = %d, want %d", tt.value, tt.cursor, got, tt.wantEnd)
+		}
+	}
+}
+
+func TestModalInputHistory(t *testing.T) {
+	m := sendKeys(searchModel(t), "/", "api", "enter", "/", "lunch", "enter")
+	if got := m.inputHistory["search"]; len(got) != 2 || got[0] != "api" || got[1] != "lunch" {
+		t.Fatalf("Expected the submitted searches in the history, got %v", got)
+	}
+
+	// Up recalls the last search; its matches then take over up and down
+	m = sendKeys(m, "/", "up")
+	if m.modalInput != "lunch" || len(m.searchMatches) != 1 {
+		t.Fatalf("Expected the last search with its match, got %q with %v", m.modalInput, m.searchMatches)
+	}
+	if m = sendKeys(m, "up"); m.modalInput != "lunch" {
+		t.Errorf("Expected up to stay in the search results, got %q", m.modalInput)
+	}
+
+	// Without matches, up and down browse the history and keep the draft
+	m.inputHistory["search"] = []string{"standup", "retro"}
+	m = sendKeys(m, "esc", "/", "zz")
+	steps := []struct {
+		key  string
+		want string
+	}{
+		{"up", "retro"},
+		{"up", "standup"},
+		{"up", "standup"},
+		{"down", "retro"},
+		{"down", "zz"},
+	}
+	for _, step := range steps {
+		m = sendKeys(m, step.key)
+		if m.modalInput != step.want || m.modalCursor != len([]rune(step.want)) {
+			t.Fatalf("After %s expected %q with the cursor at its end, got %q at %d", step.key, step.want, m.modalInput, m.modalCursor)
+		}
+	}
+}
@@ -2,38 +2,73 @@ package tui
 
 import (
 	"fmt"
+	"lazytime/config"
+	"lazytime/daemon"
 	"lazytime/storage"
 	"lazytime/tui/components"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-// ViewMode represents the active view (Today, Week, Month).
+// ViewMode represents the active view (Today, Week, Month, Year).
 type ViewMode int
 
 const (
 	ViewToday ViewMode = iota
 	ViewWeek
+	ViewMonth
+	ViewYear
+)
+
+// Pane represents what the main pane shows for the active view.
+type Pane int
+
+const (
+	PaneList     Pane = iota // Entry list
+	PaneTree                 // Tag tree
+	PaneTimeline             // 24h timeline per day
+	PaneGaps                 // Untracked gaps within working hours
+	PaneHeatmap              // 52-week heatmap
+	PaneStats                // Tag distribution charts
 )
 
 // Model represents the application state.
 type Model struct {
-	entries      []storage.Entry
-	now          time.Time
+	entries      []storage.Entry // Entries with tag aliases resolved
+	rawEntries   []storage.Entry // Entries as written in the log, for prefilling edits
+	now          time.Time       // Local time; the TUI shows and reads times in its zone
 	viewMode     ViewMode
+	rangeOffset  int // Periods before (negative) the current one shown by the view
 	message      string
 	messageError bool
-	messageTimer *time.Timer
+	messageAt    time.Time
 
 	// Modal state
 	showModal        bool
-	modalType        string // "new" or "help"
+	modalType        string // "new", "edit", "split", "confirm", "search", "filter", "idle", "reassign" or "help"
 	modalInput       string
+	modalFields      []string // Field values of the edit modal (text, start, end)
+	modalField       int      // Active field of the edit modal
+	modalError       string   // Validation error shown inside the modal
+	modalPrompt      string   // Question shown by the confirm and split modals
+	modalCursor      int      // Rune index of the cursor in the active input
 	modalSuggestions []string
+	suggestionKind   string // suggestTags or suggestText
 	modalSelected    int
+	modalTarget      storage.Entry // Entry acted on by the edit, split and confirm modals
+	searchMatches    []int         // Indices of entries matching the search modal's query
+	confirmCmd       tea.Cmd       // Action run when the confirm modal is accepted
+	helpOffset       int           // First line shown by the help modal
+
+	// Input history per modal type, browsed with up/down
+	inputHistory map[string][]string
+	historyIndex int    // Index into the history, -1 while editing
+	historyDraft string // Input being typed before browsing the history
 
 	// Hero section
 	activeEntryIndex int
@@ -42,12 +77,60 @@ type Model struct {
 	targetToday time.Duration
 	targetWeek  time.Duration
 
+	// Settings from config
+	rounding     storage.Rounding
+	tagAliases   map[string]string
+	workingHours storage.WorkingHours
+	keys         KeyMap
+	idleTimeout  time.Duration // 0 disables idle detection
+	idleCommand  string        // Prints the desktop idle time; "" uses TUI input
+
+	// Idle detection state
+	lastActivity  time.Time // Last key press or click in the TUI
+	idleCheckedAt time.Time // Last run of the idle command
+	idleStart     time.Time // Start of the current idle period, zero if not idle
+	idleFrom      time.Time // Idle period asked about by the idle modal
+	idleTo        time.Time
+
+	// Subscription to the daemon's change events, nil without a daemon
+	daemonSub     *daemon.Subscription
+	daemonTriedAt time.Time // Last attempt to subscribe
+
 	// Window size
 	width  int
 	height int
 
+	// Log file version of the loaded entries, polled for live reload
+	logStamp logStamp
+
 	// Scroll state
 	scrollOffset int
+
+	// Selected entry in the entry list (index into entries, -1 for none)
+	selected int
+
+	// Main pane content
+	pane Pane
+
+	// Tag tree state
+	treeCursor    int
+	collapsedTags map[string]bool
+
+	// Timeline state (index of the first day shown)
+	timelineOffset int
+
+	// Gap list state
+	gapCursor int
+
+	// Year heatmap state (selected day, in days before today)
+	heatmapCursor int
+
+	// Sidebar shown or hidden against the layout's default
+	sidebarToggled bool
+
+	// Tag filter restricting the lists, tree, timeline, goals and heatmap
+	// to entries with this tag or its child tags ("" for no filter)
+	tagFilter string
 }
 
 // NewModel creates a new model instance.
@@ -60,22 +143,69 @@ func NewModel() *Model {
 		width:            120,
 		height:           40,
 		scrollOffset:     0,
+		selected:         -1,
+		collapsedTags:    make(map[string]bool),
+		inputHistory:     make(map[string][]string),
+		historyIndex:     -1,
+		keys:             defaultKeyMap(),
+		lastActivity:     storage.UTCNow(),
 	}
+	m.loadConfig()
 	m.reloadEntries()
 	return m
 }
 
+// loadConfig applies settings from the config file.
+func (m *Model) loadConfig() {
+	// Each setting falls back to its default on its own, so a mistake in
+	// one does not discard the others (the theme in particular)
+	var errs []string
+	cfg, err := config.Load("")
+	if err != nil {
+		errs = append(errs, err.Error())
+		cfg = config.Config{}
+	}
+	m.tagAliases = cfg.TagAliases
+	if m.rounding, err = cfg.RoundingPolicy(); err != nil {
+		errs = append(errs, err.Error())
+		m.rounding = storage.Rounding{}
+	}
+	if m.workingHours, err = cfg.WorkingSchedule(); err != nil {
+		errs = append(errs, err.Error())
+		m.workingHours = storage.DefaultWorkingHours
+	}
+	configDir := filepath.Dir(config.DefaultConfigPath())
+	theme, err := loadTheme(cfg.Theme, configDir)
+	if err != nil {
+		errs = append(errs, err.Error())
+		theme, _ = loadTheme("auto", configDir)
+	}
+	applyTheme(theme)
+	if m.keys, err = loadKeyMap(cfg.Keys); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if m.idleTimeout, err = cfg.IdleThreshold(); err != nil {
+		errs = append(errs, err.Error())
+	}
+	m.idleCommand = strings.TrimSpace(cfg.IdleCommand)
+	if len(errs) > 0 {
+		m.message = "Error reading config: " + strings.Join(errs, "; ")
+		m.messageError = true
+	}
+}
+
 // reloadEntries reloads entries from storage.
 func (m *Model) reloadEntries() error {
+	m.logStamp = readLogStamp()
 	entries, err := storage.ReadEntries("")
 	if err != nil {
 		m.message = "Error reading log: " + err.Error()
 		m.messageError = true
-		m.entries = []storage.Entry{}
 		return err
 	}
-	m.entries = entries
-	m.now = storage.UTCNow()
+	m.rawEntries = entries
+	m.entries = storage.ApplyTagAliases(entries, m.tagAliases)
+	m.now = storage.LocalNow()
 
 	// Find active entry
 	m.activeEntryIndex = storage.FindOpen(m.entries)
@@ -96,60 +226,184 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.recordActivity() {
+			return m, nil
+		}
 		if m.showModal {
 			return m.handleModalKey(msg)
 		}
+		if m.pane == PaneTree {
+			if handled, cmd := m.handleTreeKey(msg); handled {
+				return m, cmd
+			}
+		}
+		if m.pane == PaneGaps {
+			if m.handleGapKey(msg) {
+				return m, nil
+			}
+		}
+		if m.pane == PaneHeatmap {
+			if m.handleHeatmapKey(msg) {
+				return m, nil
+			}
+		}
 
-		switch msg.String() {
-		case "q", "esc":
+		keys := m.keys
+		switch {
+		case keys.Quit.Matches(msg):
 			return m, tea.Quit
-		case "1":
-			m.viewMode = ViewToday
-			m.scrollOffset = 0 // Reset scroll when switching views
-		case "2":
-			m.viewMode = ViewWeek
-			m.scrollOffset = 0 // Reset scroll when switching views
-		case "up", "k":
-			// Scroll up (only in Today or Week view)
-			if m.viewMode == ViewToday || m.viewMode == ViewWeek {
-				if m.scrollOffset > 0 {
-					m.scrollOffset--
-				}
+		case keys.ViewToday.Matches(msg):
+			m.setViewMode(ViewToday)
+		case keys.ViewWeek.Matches(msg):
+			m.setViewMode(ViewWeek)
+		case keys.ViewMonth.Matches(msg):
+			m.setViewMode(ViewMonth)
+		case keys.ViewYear.Matches(msg):
+			m.setViewMode(ViewYear)
+		case keys.PrevRange.Matches(msg):
+			m.shiftRange(-1)
+		case keys.NextRange.Matches(msg):
+			m.shiftRange(1)
+		case keys.Tree.Matches(msg):
+			m.togglePane(PaneTree)
+		case keys.Timeline.Matches(msg):
+			m.togglePane(PaneTimeline)
+		case keys.Gaps.Matches(msg):
+			m.togglePane(PaneGaps)
+		case keys.Heatmap.Matches(msg):
+			m.togglePane(PaneHeatmap)
+		case keys.Stats.Matches(msg):
+			m.togglePane(PaneStats)
+		case keys.Sidebar.Matches(msg):
+			m.sidebarToggled = !m.sidebarToggled
+		case keys.Up.Matches(msg):
+			if m.pane == PaneTimeline {
+				m.scrollTimeline(-1)
+			} else {
+				m.moveSelection(-1)
 			}
 			return m, nil
-		case "down", "j":
-			// Scroll down (only in Today or Week view)
-			if m.viewMode == ViewToday || m.viewMode == ViewWeek {
-				m.scrollOffset++
-				// Will be clamped in render functions based on actual content
+		case keys.Down.Matches(msg):
+			if m.pane == PaneTimeline {
+				m.scrollTimeline(1)
+			} else {
+				m.moveSelection(1)
 			}
 			return m, nil
-		case "n":
-			m.showModal = true
-			m.modalType = "new"
-			m.modalInput = ""
-			m.modalSuggestions = []string{}
-			m.modalSelected = 0
-		case "x":
+		case keys.New.Matches(msg):
+			m.openTextModal("new", "", storage.Entry{})
+		case keys.Search.Matches(msg):
+			m.openTextModal("search", "", storage.Entry{})
+			m.searchMatches = nil
+		case keys.Filter.Matches(msg):
+			m.openTextModal("filter", "", storage.Entry{})
+			m.updateSuggestions()
+		case keys.ClearFilter.Matches(msg):
+			if m.tagFilter != "" {
+				m.setTagFilter("")
+				m.setMessage("Filter cleared", false)
+			}
+		case keys.Edit.Matches(msg):
+			if entry, ok := m.selectedEntry(); ok {
+				m.openEditModal(entry)
+			} else {
+				m.setMessage("Select an entry first", true)
+			}
+		case keys.Duplicate.Matches(msg):
+			// Duplicate: prefill a new entry with the selected entry's text
+			if entry, ok := m.selectedEntry(); ok {
+				m.openTextModal("new", entry.Text+" ", storage.Entry{})
+			} else {
+				m.setMessage("Select an entry first", true)
+			}
+		case keys.Resume.Matches(msg):
+			if entry, ok := m.selectedEntry(); ok {
+				return m, resumeEntryCmd(entry)
+			}
+			m.setMessage("Select an entry first", true)
+		case keys.Split.Matches(msg):
+			if entry, ok := m.selectedEntry(); ok {
+				end := m.now
+				if entry.End != nil {
+					end = *entry.End
+				}
+				mid := entry.Start.Add(end.Sub(entry.Start) / 2).In(m.now.Location())
+				m.openTextModal("split", mid.Format("15:04"), entry)
+				m.modalPrompt = "Split \"" + entry.Text + "\" at (HH:MM):"
+			} else {
+				m.setMessage("Select an entry first", true)
+			}
+		case keys.Delete.Matches(msg):
+			if entry, ok := m.selectedEntry(); ok {
+				m.showModal = true
+				m.modalType = "confirm"
+				m.modalTarget = entry
+				m.modalPrompt = "Delete \"" + entry.Text + "\"?"
+				m.confirmCmd = deleteEntryCmd(entry)
+			} else {
+				m.setMessage("Select an entry first", true)
+			}
+		case keys.Stop.Matches(msg):
 			return m, m.stopEntry()
-		case "r":
+		case keys.Reload.Matches(msg):
 			return m, loadEntriesCmd()
-		case "e", "?":
+		case keys.Help.Matches(msg):
 			m.showModal = true
 			m.modalType = "help"
+			m.helpOffset = 0
 		}
+	case tea.MouseMsg:
+		if msg.Action == tea.MouseActionPress && m.recordActivity() {
+			return m, nil
+		}
+		return m.handleMouse(msg)
+	case idleCheckedMsg:
+		m.handleIdleChecked(msg)
+	case daemonSubscribedMsg, daemonEventMsg:
+		return m, m.handleDaemonMsg(msg)
 	case tea.WindowSizeMsg:
+		// Crossing a layout breakpoint restores the sidebar's default
+		if layoutModeFor(max(msg.Width, tinyWidth)) != m.layout().mode {
+			m.sidebarToggled = false
+		}
 		m.width = msg.Width
 		m.height = msg.Height
 		return m, nil
 	case tickMsg:
-		m.now = storage.UTCNow()
+		m.now = storage.LocalNow()
 		m.activeEntryIndex = storage.FindOpen(m.entries)
-		return m, tickCmd()
+		// Clear the status message after 3 seconds
+		if m.message != "" && time.Since(m.messageAt) > 3*time.Second {
+			m.message = ""
+		}
+		cmds = append(cmds, tickCmd(), m.checkIdle(), m.checkDaemon())
+		// Reload when the log was changed by another process
+		if stamp := readLogStamp(); stamp != m.logStamp {
+			m.logStamp = stamp
+			cmds = append(cmds, loadEntriesCmd())
+		}
+		return m, tea.Batch(cmds...)
 	case entriesLoadedMsg:
-		m.entries = msg.entries
-		m.now = storage.UTCNow()
-		m.activeEntryIndex = storage.FindOpen(m.entries)
+		m.logStamp = msg.stamp
+		if msg.err != nil {
+			// Keep showing the entries read last
+			m.setMessage("Error reading log: "+msg.err.Error(), true)
+			return m, nil
+		}
+		m.setEntries(msg.entries)
+	case entryActionMsg:
+		if msg.err != nil && m.showModal && m.modalType == "edit" {
+			// Keep the edit modal open so the conflict can be fixed
+			m.modalError = msg.err.Error()
+			return m, nil
+		}
+		if msg.err != nil {
+			m.setMessage("Error: "+msg.err.Error(), true)
+		} else {
+			m.setMessage(msg.message, false)
+		}
+		m.closeModal()
+		return m, loadEntriesCmd()
 	case entryStoppedMsg:
 		if msg.err != nil {
 			m.message = "Error: " + msg.err.Error()
@@ -158,7 +412,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.message = "Stopped: " + msg.text
 			m.messageError = false
 		}
-		m.setMessageTimer()
+		m.messageAt = time.Now()
 		return m, loadEntriesCmd()
 	case entryStartedMsg:
 		if msg.err != nil {
@@ -168,34 +422,328 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.message = "Started: " + msg.text
 			m.messageError = false
 		}
-		m.setMessageTimer()
+		m.messageAt = time.Now()
 		// Close modal and reset state
-		m.showModal = false
-		m.modalInput = ""
-		m.modalSuggestions = []string{}
-		m.modalSelected = 0
+		m.closeModal()
 		return m, loadEntriesCmd()
 	}
 
 	return m, tea.Batch(cmds...)
 }
 
-// setMessageTimer sets a timer to clear the message after 3 seconds.
-func (m *Model) setMessageTimer() {
-	if m.messageTimer != nil {
-		m.messageTimer.Stop()
+// handleTreeKey handles navigation and expand/collapse in the tag tree.
+// Returns false if the key is not a tree key.
+func (m *Model) handleTreeKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	startUTC, endUTC := m.viewRange()
+	groups := m.tagTree(startUTC, endUTC)
+	visible := components.VisibleTagGroups(groups, m.collapsedTags)
+	if len(visible) == 0 {
+		return false, nil
+	}
+	if m.treeCursor >= len(visible) {
+		m.treeCursor = len(visible) - 1
+	}
+	current := visible[m.treeCursor]
+
+	keys := m.keys
+	levelKey := keys.CollapseLevel.Matches(msg) || keys.ExpandLevel.Matches(msg)
+	switch {
+	case keys.Up.Matches(msg):
+		if m.treeCursor > 0 {
+			m.treeCursor--
+		}
+	case keys.Down.Matches(msg):
+		if m.treeCursor < len(visible)-1 {
+			m.treeCursor++
+		}
+	case keys.Select.Matches(msg), keys.ToggleNode.Matches(msg):
+		m.collapsedTags[current.Tag] = !m.collapsedTags[current.Tag]
+	case keys.Right.Matches(msg):
+		m.collapsedTags[current.Tag] = false
+	case keys.Left.Matches(msg):
+		// Collapse the node, or jump to its parent if already collapsed
+		parent := storage.TagParent(current.Tag)
+		if !m.collapsedTags[current.Tag] {
+			m.collapsedTags[current.Tag] = true
+		} else if parent != "" {
+			for i, group := range visible {
+				if group.Tag == parent {
+					m.treeCursor = i
+					break
+				}
+			}
+		}
+	case keys.CollapseLevel.Matches(msg):
+		// Collapse every node at the cursor's level
+		for _, group := range groups {
+			if group.Depth == current.Depth {
+				m.collapsedTags[group.Tag] = true
+			}
+		}
+	case keys.ExpandLevel.Matches(msg):
+		// Expand every node at the cursor's level
+		for _, group := range groups {
+			if group.Depth == current.Depth {
+				m.collapsedTags[group.Tag] = false
+			}
+		}
+	default:
+		return false, nil
+	}
+
+	// Keep the cursor on the same tag when rows above it were hidden or shown
+	if levelKey {
+		for i, group := range components.VisibleTagGroups(groups, m.collapsedTags) {
+			if group.Tag == current.Tag {
+				m.treeCursor = i
+				break
+			}
+		}
+	}
+	return true, nil
+}
+
+// minGap is the shortest untracked interval listed in the gaps pane.
+const minGap = 5 * time.Minute
+
+// gaps returns the untracked gaps within working hours in the view's range.
+// Working hours are wall-clock times in the TUI's (local) zone.
+func (m Model) gaps() []storage.Gap {
+	startUTC, endUTC := m.viewRange()
+	return storage.FindGaps(m.entries, startUTC, endUTC, m.now, m.workingHours, m.now.Location(), minGap)
+}
+
+// handleGapKey handles navigation keys in the gaps pane. Enter opens the
+// new entry modal prefilled with the selected gap's start and end.
+func (m *Model) handleGapKey(msg tea.KeyMsg) bool {
+	gaps := m.gaps()
+	if len(gaps) == 0 {
+		return false
+	}
+	m.gapCursor = min(m.gapCursor, len(gaps)-1)
+
+	switch {
+	case m.keys.Up.Matches(msg):
+		m.gapCursor = max(0, m.gapCursor-1)
+	case m.keys.Down.Matches(msg):
+		m.gapCursor = min(len(gaps)-1, m.gapCursor+1)
+	case m.keys.Select.Matches(msg):
+		m.openTextModal("new", gapOverrides(gaps[m.gapCursor], m.now), storage.Entry{})
+	default:
+		return false
+	}
+	return true
+}
+
+// handleHeatmapKey moves the year heatmap's cursor: up/down by a day,
+// left/right by a week. Enter opens the selected day in the Today view.
+func (m *Model) handleHeatmapKey(msg tea.KeyMsg) bool {
+	switch {
+	case m.keys.Up.Matches(msg):
+		m.moveHeatmapCursor(1)
+	case m.keys.Down.Matches(msg):
+		m.moveHeatmapCursor(-1)
+	case m.keys.Left.Matches(msg):
+		m.moveHeatmapCursor(7)
+	case m.keys.Right.Matches(msg):
+		m.moveHeatmapCursor(-7)
+	case m.keys.Select.Matches(msg):
+		daysAgo := m.heatmapCursor
+		m.pane = PaneList
+		m.setViewMode(ViewToday)
+		m.rangeOffset = -daysAgo
+		m.clampSelection()
+	default:
+		return false
+	}
+	return true
+}
+
+// moveHeatmapCursor moves the year heatmap's cursor by days (positive is
+// earlier), staying between today and the heatmap's first day.
+func (m *Model) moveHeatmapCursor(days int) {
+	start := components.YearHeatmapStart(m.now, m.layout().leftWidth)
+	today := time.Date(m.now.Year(), m.now.Month(), m.now.Day(), 0, 0, 0, 0, m.now.Location())
+	maxCursor := int(today.Sub(start).Hours() / 24)
+	m.heatmapCursor = min(maxCursor, max(0, m.heatmapCursor+days))
+}
+
+// scrollTimeline moves the timeline's first day by delta days.
+func (m *Model) scrollTimeline(delta int) {
+	m.timelineOffset = min(max(0, m.timelineOffset+delta), max(0, m.timelineDays()-1))
+}
+
+// gapOverrides formats a gap as the @start @end time overrides of the new
+// entry modal, using the short @HH:MM form for gaps today.
+func gapOverrides(gap storage.Gap, nowLocal time.Time) string {
+	tz := nowLocal.Location()
+	startLocal := gap.Start.In(tz)
+	endLocal := gap.End.In(tz)
+	layout := "2006-01-02T15:04"
+	if startLocal.Format("2006-01-02") == nowLocal.Format("2006-01-02") && endLocal.Format("2006-01-02") == nowLocal.Format("2006-01-02") {
+		layout = "15:04"
+	}
+	return "@" + startLocal.Format(layout) + " @" + endLocal.Format(layout) + " "
+}
+
+// setViewMode switches to the given view showing the current period.
+func (m *Model) setViewMode(mode ViewMode) {
+	m.viewMode = mode
+	m.rangeOffset = 0
+	m.scrollOffset = 0 // Reset scroll when switching views
+	m.resetPaneCursors()
+	m.clampSelection()
+}
+
+// togglePane shows pane in the main pane, or the entry list if it is
+// already shown.
+func (m *Model) togglePane(pane Pane) {
+	if m.pane == pane {
+		m.pane = PaneList
+	} else {
+		m.pane = pane
+	}
+	m.resetPaneCursors()
+}
+
+// setTagFilter restricts the views to entries with tag (or its child tags);
+// an empty tag clears the filter.
+func (m *Model) setTagFilter(tag string) {
+	m.tagFilter = tag
+	m.scrollOffset = 0
+	m.resetPaneCursors()
+	m.clampSelection()
+}
+
+// filterTags returns the tags offered by the filter picker: every tag in
+// use together with its parent tags, as their storage.TagKey and in tree
+// order.
+func filterTags(entries []storage.Entry) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, entry := range entries {
+		for _, path := range entry.TagPaths() {
+			path = storage.TagKey(path)
+			if !seen[path] {
+				seen[path] = true
+				tags = append(tags, path)
+			}
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return storage.CompareTags(tags[i], tags[j]) < 0
+	})
+	return tags
+}
+
+// matchesFilter reports whether entry passes the tag filter.
+func (m Model) matchesFilter(entry storage.Entry) bool {
+	return m.tagFilter == "" || entry.HasTagWithin(m.tagFilter)
+}
+
+// filteredEntries returns the entries passing the tag filter.
+func (m Model) filteredEntries() []storage.Entry {
+	if m.tagFilter == "" {
+		return m.entries
+	}
+	var entries []storage.Entry
+	for _, entry := range m.entries {
+		if m.matchesFilter(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// resetPaneCursors moves the tree, timeline and gap cursors back to the top.
+func (m *Model) resetPaneCursors() {
+	m.treeCursor = 0
+	m.timelineOffset = 0
+	m.gapCursor = 0
+	m.heatmapCursor = 0
+}
+
+// timelineDays returns the number of days shown by the timeline.
+func (m Model) timelineDays() int {
+	startUTC, endUTC := m.viewRange()
+	return len(components.TimelineDays(startUTC, endUTC, m.now))
+}
+
+// shiftRange moves the view's range by delta periods, stopping at the
+// current period.
+func (m *Model) shiftRange(delta int) {
+	offset := m.rangeOffset + delta
+	if offset > 0 {
+		offset = 0
+	}
+	if offset == m.rangeOffset {
+		return
+	}
+	m.rangeOffset = offset
+	m.scrollOffset = 0
+	m.resetPaneCursors()
+	m.clampSelection()
+}
+
+// setMessage shows a status message in the footer; it is cleared on the
+// first tick after 3 seconds.
+func (m *Model) setMessage(message string, isError bool) {
+	m.message = message
+	m.messageError = isError
+	m.messageAt = time.Now()
+}
+
+// openTextModal opens a modal with a text input prefilled with input.
+func (m *Model) openTextModal(modalType, input string, target storage.Entry) {
+	m.showModal = true
+	m.modalType = modalType
+	m.modalInput = input
+	m.modalCursor = len([]rune(input))
+	m.historyIndex = -1
+	m.historyDraft = ""
+	m.modalPrompt = ""
+	m.modalTarget = target
+	m.modalError = ""
+	m.modalSuggestions = []string{}
+	m.modalSelected = 0
+}
+
+// openEditModal opens the edit modal with the entry's text, start and end.
+func (m *Model) openEditModal(entry storage.Entry) {
+	m.openTextModal("edit", "", entry)
+	tz := m.now.Location()
+	end := ""
+	if entry.End != nil {
+		end = entry.End.In(tz).Format(editTimeLayout)
 	}
-	m.messageTimer = time.NewTimer(3 * time.Second)
-	go func() {
-		<-m.messageTimer.C
-		m.message = ""
-	}()
+	m.modalFields = []string{entry.Text, entry.Start.In(tz).Format(editTimeLayout), end}
+	m.modalField = editFieldText
+	m.modalCursor = len([]rune(entry.Text))
+}
+
+// closeModal hides the modal and resets its state.
+func (m *Model) closeModal() {
+	m.showModal = false
+	m.modalInput = ""
+	m.modalCursor = 0
+	m.historyIndex = -1
+	m.modalFields = nil
+	m.modalField = 0
+	m.modalError = ""
+	m.modalPrompt = ""
+	m.modalSuggestions = []string{}
+	m.modalSelected = 0
+	m.confirmCmd = nil
+	m.searchMatches = nil
+	m.helpOffset = 0
 }
 
 // Messages for Bubbletea
 type tickMsg time.Time
 type entriesLoadedMsg struct {
 	entries []storage.Entry
+	stamp   logStamp // Stamp of the log before it was read
+	err     error
 }
 type entryStoppedMsg struct {
 	text string
@@ -215,43 +763,43 @@ func tickCmd() tea.Cmd {
 
 func loadEntriesCmd() tea.Cmd {
 	return func() tea.Msg {
+		stamp := readLogStamp()
 		entries, err := storage.ReadEntries("")
 		if err != nil {
-			return entriesLoadedMsg{entries: []storage.Entry{}}
+			return entriesLoadedMsg{stamp: stamp, err: err}
 		}
-		return entriesLoadedMsg{entries: entries}
+		return entriesLoadedMsg{entries: entries, stamp: stamp}
 	}
 }
 
 func (m *Model) stopEntry() tea.Cmd {
 	return func() tea.Msg {
-		entries, err := storage.ReadEntries("")
-		if err != nil {
-			return entryStoppedMsg{err: err}
-		}
-
-		idx := storage.FindOpen(entries)
-		if idx == -1 {
-			return entryStoppedMsg{err: &noActiveEntryError{}}
-		}
-
-		now := storage.ToUTC(storage.LocalNow())
-		openEntry := entries[idx]
-		if now.Before(openEntry.Start) || now.Equal(openEntry.Start) {
-			now = openEntry.Start.Add(time.Minute)
-		}
+		var text string
+		err := daemon.Update(daemon.SocketPath(), func(entries []storage.Entry) ([]storage.Entry, error) {
+			idx := storage.FindOpen(entries)
+			if idx == -1 {
+				return nil, &noActiveEntryError{}
+			}
 
-		entries[idx] = storage.Entry{
-			Start: openEntry.Start,
-			End:   &now,
-			Text:  openEntry.Text,
-		}
+			now := storage.ToUTC(storage.LocalNow())
+			openEntry := entries[idx]
+			if now.Before(openEntry.Start) || now.Equal(openEntry.Start) {
+				now = openEntry.Start.Add(time.Minute)
+			}
 
-		if err := storage.WriteEntries(entries, ""); err != nil {
+			entries[idx] = storage.Entry{
+				Start: openEntry.Start,
+				End:   &now,
+				Text:  openEntry.Text,
+			}
+			text = openEntry.Text
+			return entries, nil
+		})
+		if err != nil {
 			return entryStoppedMsg{err: err}
 		}
 
-		return entryStoppedMsg{text: openEntry.Text}
+		return entryStoppedMsg{text: text}
 	}
 }
 
@@ -271,92 +819,254 @@ func (m Model) View() string {
 
 // handleModalKey handles keyboard input when modal is shown.
 func (m Model) handleModalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	key := msg.String()
-	
-	// Handle e/q specially for help modal only
-	if (key == "e" || key == "q") && m.modalType == "help" {
-		m.showModal = false
+	keys := m.keys
+
+	// Help modal scrolls and closes with its own key or any close key
+	if m.modalType == "help" {
+		switch {
+		case keys.Up.Matches(msg):
+			m.helpOffset = max(0, m.helpOffset-1)
+		case keys.Down.Matches(msg):
+			maxOffset := len(m.helpLines()) - components.HelpVisibleLines(m.layout().height)
+			m.helpOffset = min(m.helpOffset+1, max(0, maxOffset))
+		case keys.Help.Matches(msg), keys.Quit.Matches(msg), keys.Cancel.Matches(msg), keys.Confirm.Matches(msg):
+			m.closeModal()
+		}
 		return m, nil
 	}
-	
-	switch key {
-	case "esc":
-		m.showModal = false
-		m.modalInput = ""
-		m.modalSuggestions = []string{}
+
+	// Idle modal keeps, discards or reassigns the idle period
+	if m.modalType == "idle" {
+		switch {
+		case keys.KeepIdle.Matches(msg), keys.Cancel.Matches(msg):
+			m.closeModal()
+		case keys.DiscardIdle.Matches(msg):
+			target, from, to := m.modalTarget, m.idleFrom, m.idleTo
+			m.closeModal()
+			return m, splitIdleCmd(target, from, to, "")
+		case keys.ReassignIdle.Matches(msg):
+			m.modalType = "reassign"
+			m.modalPrompt = "Log " + FormatDurationShort(m.idleTo.Sub(m.idleFrom)) + " from " +
+				m.idleFrom.In(m.now.Location()).Format("15:04") + " as:"
+			m.updateSuggestions()
+		}
+		return m, nil
+	}
+
+	// Confirm modal only answers yes or no
+	if m.modalType == "confirm" {
+		switch {
+		case keys.Yes.Matches(msg), keys.Confirm.Matches(msg):
+			cmd := m.confirmCmd
+			m.closeModal()
+			return m, cmd
+		case keys.No.Matches(msg), keys.Cancel.Matches(msg), keys.Quit.Matches(msg):
+			m.closeModal()
+		}
 		return m, nil
-	case "enter":
-		if m.modalType == "new" {
+	}
+
+	// Arrow keys pick suggestions and results or browse the history; all
+	// other keys not bound to a modal action edit the text input
+	switch {
+	case keys.Cancel.Matches(msg) && m.modalType == "reassign":
+		// Back to the idle prompt
+		target, from, to := m.modalTarget, m.idleFrom, m.idleTo
+		m.closeModal()
+		m.openTextModal("idle", "", target)
+		m.idleFrom, m.idleTo = from, to
+		m.modalPrompt = m.idlePrompt()
+		return m, nil
+	case keys.Cancel.Matches(msg):
+		m.closeModal()
+		return m, nil
+	case keys.Confirm.Matches(msg):
+		switch m.modalType {
+		case "new":
+			m.recordHistory(m.modalType, m.modalInput)
 			return m, m.startEntry()
-		} else if m.modalType == "help" {
-			m.showModal = false
+		case "edit":
+			updated, err := parseEditFields(m.modalFields, m.modalTarget, m.now)
+			if err != nil {
+				m.modalError = err.Error()
+				return m, nil
+			}
+			return m, updateEntryCmd(m.modalTarget, updated, m.now.Location())
+		case "reassign":
+			text := strings.TrimSpace(m.modalInput)
+			if text == "" {
+				m.modalError = "enter the text to log the idle time as"
+				return m, nil
+			}
+			m.recordHistory(m.modalType, m.modalInput)
+			return m, splitIdleCmd(m.modalTarget, m.idleFrom, m.idleTo, text)
+		case "split":
+			at, err := parseSplitTime(strings.TrimSpace(m.modalInput), m.modalTarget, m.now.Location())
+			if err != nil {
+				m.modalError = err.Error()
+				return m, nil
+			}
+			m.recordHistory(m.modalType, m.modalInput)
+			return m, splitEntryCmd(m.modalTarget, at)
+		case "filter":
+			if len(m.modalSuggestions) > 0 {
+				m.recordHistory(m.modalType, m.modalInput)
+				m.setTagFilter(m.modalSuggestions[m.modalSelected])
+				m.closeModal()
+			}
+			return m, nil
+		case "search":
+			if len(m.searchMatches) > 0 {
+				m.recordHistory(m.modalType, m.modalInput)
+				idx := m.searchMatches[m.modalSelected]
+				m.closeModal()
+				m.jumpToEntry(idx)
+			}
 			return m, nil
 		}
-	case "up":
-		if len(m.modalSuggestions) > 0 {
+	case keys.Complete.Matches(msg), keys.PrevField.Matches(msg):
+		next := keys.Complete.Matches(msg)
+		// Complete the highlighted suggestion
+		if next && len(m.modalSuggestions) > 0 && m.modalType != "filter" {
+			m.acceptSuggestion()
+			return m, nil
+		}
+		// Move between the edit modal's fields
+		if m.modalType == "edit" {
+			step := 1
+			if !next {
+				step = len(m.modalFields) - 1
+			}
+			m.modalField = (m.modalField + step) % len(m.modalFields)
+			m.modalCursor = len([]rune(m.modalFields[m.modalField]))
+			m.updateSuggestions()
+		}
+		return m, nil
+	case msg.Type == tea.KeyUp:
+		if len(m.modalSuggestions) > 0 || (m.modalType == "search" && len(m.searchMatches) > 0) {
 			m.modalSelected = max(0, m.modalSelected-1)
+		} else if m.modalType != "edit" {
+			m.browseHistory(-1)
 		}
 		return m, nil
-	case "down":
+	case msg.Type == tea.KeyDown:
 		if len(m.modalSuggestions) > 0 {
 			m.modalSelected = min(len(m.modalSuggestions)-1, m.modalSelected+1)
+		} else if m.modalType == "search" && len(m.searchMatches) > 0 {
+			m.modalSelected = min(len(m.searchMatches)-1, m.modalSelected+1)
+		} else if m.modalType != "edit" {
+			m.browseHistory(1)
 		}
 		return m, nil
 	default:
-		// Handle text input (including e/q for new entry modal)
-		if m.modalType == "new" {
-			if msg.Type == tea.KeyRunes {
-				m.modalInput += string(msg.Runes)
-				// Update tag suggestions if user is typing a tag
-				tagInput := extractCurrentTagInput(m.modalInput)
-				if tagInput != "" {
-					allTags := GetUniqueTags(m.entries)
-					m.modalSuggestions = components.GetFuzzySuggestions(tagInput, allTags, 5)
-				} else {
-					m.modalSuggestions = []string{}
-				}
-			} else if msg.Type == tea.KeySpace {
-				m.modalInput += " "
-				// Update tag suggestions if user is typing a tag
-				tagInput := extractCurrentTagInput(m.modalInput)
-				if tagInput != "" {
-					allTags := GetUniqueTags(m.entries)
-					m.modalSuggestions = components.GetFuzzySuggestions(tagInput, allTags, 5)
-				} else {
-					m.modalSuggestions = []string{}
-				}
-			} else if msg.Type == tea.KeyBackspace {
-				if len(m.modalInput) > 0 {
-					m.modalInput = m.modalInput[:len(m.modalInput)-1]
-					// Update tag suggestions if user is typing a tag
-					tagInput := extractCurrentTagInput(m.modalInput)
-					if tagInput != "" {
-						allTags := GetUniqueTags(m.entries)
-						m.modalSuggestions = components.GetFuzzySuggestions(tagInput, allTags, 5)
-					} else {
-						m.modalSuggestions = []string{}
-					}
-				}
+		value, cursor, handled := editLine(m.activeInput(), m.modalCursor, msg)
+		if !handled {
+			return m, nil
+		}
+		changed := value != m.activeInput()
+		m.setActiveInput(value)
+		m.modalCursor = cursor
+		if m.modalType == "search" {
+			if changed {
+				m.searchMatches = searchEntries(m.entries, m.modalInput)
+				m.modalSelected = 0
 			}
+		} else {
+			// Moving the cursor can enter or leave a tag
+			m.updateSuggestions()
+		}
+		if changed {
+			m.historyIndex = -1
 		}
 	}
 	return m, nil
 }
 
-// extractCurrentTagInput extracts the current tag being typed (text after the last #).
+// activeInput returns the value of the modal's focused text input.
+func (m Model) activeInput() string {
+	if m.modalType == "edit" {
+		return m.modalFields[m.modalField]
+	}
+	return m.modalInput
+}
+
+// setActiveInput sets the value of the modal's focused text input and
+// moves the cursor to its end.
+func (m *Model) setActiveInput(value string) {
+	m.modalCursor = len([]rune(value))
+	if m.modalType == "edit" {
+		m.modalFields[m.modalField] = value
+		return
+	}
+	m.modalInput = value
+}
+
+// inputBeforeCursor returns the part of the active input before the cursor.
+func (m Model) inputBeforeCursor() string {
+	runes := []rune(m.activeInput())
+	return string(runes[:min(max(0, m.modalCursor), len(runes))])
+}
+
+// updateSuggestions refreshes the suggestions below the modal input: tags
+// while a tag is being typed, otherwise past entry texts in the new entry
+// modal.
+func (m *Model) updateSuggestions() {
+	m.suggestionKind = suggestTags
+	if m.modalType == "filter" {
+		// The whole input narrows the tag list; an empty input lists all tags
+		m.modalSuggestions = components.GetFuzzySuggestions(strings.TrimPrefix(m.modalInput, "#"), filterTags(m.entries), 5)
+		m.modalSelected = min(m.modalSelected, max(0, len(m.modalSuggestions)-1))
+		return
+	}
+	tagInput := ""
+	textModal := m.modalType == "new" || m.modalType == "reassign"
+	if textModal || (m.modalType == "edit" && m.modalField == editFieldText) {
+		tagInput = extractCurrentTagInput(m.inputBeforeCursor())
+	}
+	if tagInput != "" {
+		allTags := GetUniqueTags(m.entries)
+		m.modalSuggestions = components.GetFuzzySuggestions(tagInput, allTags, 5)
+	} else if textModal {
+		m.suggestionKind = suggestText
+		m.modalSuggestions = textSuggestions(m.entries, textQuery(m.modalInput), m.now, 5)
+	} else {
+		m.modalSuggestions = []string{}
+	}
+	m.modalSelected = min(m.modalSelected, max(0, len(m.modalSuggestions)-1))
+}
+
+// acceptSuggestion applies the highlighted suggestion to the modal input.
+func (m *Model) acceptSuggestion() {
+	suggestion := m.modalSuggestions[m.modalSelected]
+	if m.suggestionKind == suggestText {
+		m.setActiveInput(completeText(m.activeInput(), suggestion))
+	} else {
+		// Complete the tag at the cursor, keeping the text after it
+		before := m.inputBeforeCursor()
+		after := string([]rune(m.activeInput())[len([]rune(before)):])
+		completed := completeTag(before, suggestion)
+		m.setActiveInput(completed + strings.TrimLeft(after, " "))
+		m.modalCursor = len([]rune(completed))
+	}
+	m.modalSelected = 0
+	m.updateSuggestions()
+}
+
+// extractCurrentTagInput extracts the tag being typed: the last word of
+// the input if it starts with #.
 func extractCurrentTagInput(input string) string {
-	lastHash := strings.LastIndex(input, "#")
-	if lastHash == -1 {
+	if input == "" || strings.HasSuffix(input, " ") {
+		return ""
+	}
+	words := strings.Fields(input)
+	if len(words) == 0 {
 		return ""
 	}
-	// Get text after the last #
-	tagPart := input[lastHash+1:]
-	// Extract only the current word (up to space or end)
-	words := strings.Fields(tagPart)
-	if len(words) > 0 {
-		return words[0]
+	last := words[len(words)-1]
+	if !strings.HasPrefix(last, "#") {
+		return ""
 	}
-	return tagPart
+	return last[1:]
 }
 
 // startEntry starts a new entry (command).
@@ -368,14 +1078,6 @@ func (m *Model) startEntry() tea.Cmd {
 		}
 
 		nowLocal := storage.LocalNow()
-		entries, err := storage.ReadEntries("")
-		if err != nil {
-			return entryStartedMsg{err: err}
-		}
-
-		if storage.FindOpen(entries) != -1 {
-			return entryStartedMsg{err: &entryAlreadyRunningError{}}
-		}
 
 		// Parse time overrides (@HH:MM)
 		cleanText, startOverride, endOverride, err := parseTimeOverrides(text, nowLocal)
@@ -407,15 +1109,17 @@ func (m *Model) startEntry() tea.Cmd {
 				Text:  cleanText,
 			}
 
-			overlapEntry, overlapDuration, hasOverlap := storage.CheckOverlap(entries, newEntry, *newEntry.End)
-			if hasOverlap {
-				return entryStartedMsg{err: &overlapError{
-					entry:    overlapEntry,
-					duration: overlapDuration,
-				}}
-			}
-
-			if err := storage.AppendEntry(newEntry, ""); err != nil {
+			err := daemon.Update(daemon.SocketPath(), func(entries []storage.Entry) ([]storage.Entry, error) {
+				overlapEntry, overlapDuration, hasOverlap := storage.CheckOverlap(entries, newEntry, *newEntry.End)
+				if hasOverlap {
+					return nil, &overlapError{
+						entry:    overlapEntry,
+						duration: overlapDuration,
+					}
+				}
+				return append(entries, newEntry), nil
+			})
+			if err != nil {
 				return entryStartedMsg{err: err}
 			}
 
@@ -432,11 +1136,17 @@ func (m *Model) startEntry() tea.Cmd {
 			return entryStartedMsg{err: &invalidTimeError{msg: "start time cannot be in the future"}}
 		}
 
-		if err := storage.AppendEntry(storage.Entry{
-			Start: storage.ToUTC(*startLocal),
-			End:   nil,
-			Text:  cleanText,
-		}, ""); err != nil {
+		err = daemon.Update(daemon.SocketPath(), func(entries []storage.Entry) ([]storage.Entry, error) {
+			if storage.FindOpen(entries) != -1 {
+				return nil, &entryAlreadyRunningError{}
+			}
+			return append(entries, storage.Entry{
+				Start: storage.ToUTC(*startLocal),
+				End:   nil,
+				Text:  cleanText,
+			}), nil
+		})
+		if err != nil {
 			return entryStartedMsg{err: err}
 		}
 
@@ -474,10 +1184,10 @@ func (e *overlapError) Error() string {
 	return "entry overlaps with existing entry"
 }
 
-// parseTimeOverrides extracts @HH:MM tokens from text.
-// Returns cleaned text, optional start time, optional end time.
+// parseTimeOverrides extracts @HH:MM (today) and @YYYY-MM-DDTHH:MM tokens
+// from text. Returns cleaned text, optional start time, optional end time.
 func parseTimeOverrides(rawText string, now time.Time) (string, *time.Time, *time.Time, error) {
-	re := regexp.MustCompile(`@(\d{1,2}:\d{2})`)
+	re := regexp.MustCompile(`@(?:(\d{4}-\d{2}-\d{2})T)?(\d{1,2}:\d{2})`)
 	matches := re.FindAllStringSubmatch(rawText, -1)
 	if len(matches) == 0 {
 		return strings.TrimSpace(rawText), nil, nil, nil
@@ -490,12 +1200,20 @@ func parseTimeOverrides(rawText string, now time.Time) (string, *time.Time, *tim
 	today := now
 	var parsedTimes []time.Time
 	for _, match := range matches {
-		timeText := match[1]
+		day := today
+		if match[1] != "" {
+			parsed, err := storage.ParseDate(match[1])
+			if err != nil {
+				return "", nil, nil, fmt.Errorf("invalid date: %s", match[1])
+			}
+			day = parsed
+		}
+		timeText := match[2]
 		hour, minute, err := storage.ParseTimeOfDay(timeText)
 		if err != nil {
 			return "", nil, nil, fmt.Errorf("invalid time: %s", timeText)
 		}
-		parsedTimes = append(parsedTimes, time.Date(today.Year(), today.Month(), today.Day(), hour, minute, 0, 0, tz))
+		parsedTimes = append(parsedTimes, time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, tz))
 	}
 
 	// Remove @HH:MM from text
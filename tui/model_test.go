@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"lazytime/storage"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// testModel returns a model with NewModel's defaults showing entries at
+// now, without reading the config or the log.
+func testModel(t *testing.T, now time.Time, entries ...storage.Entry) Model {
+	t.Helper()
+	keys, err := loadKeyMap(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Model{
+		entries:          entries,
+		rawEntries:       entries,
+		now:              now,
+		viewMode:         ViewToday,
+		targetToday:      8 * time.Hour,
+		targetWeek:       40 * time.Hour,
+		activeEntryIndex: storage.FindOpen(entries),
+		workingHours:     storage.DefaultWorkingHours,
+		width:            120,
+		height:           40,
+		selected:         -1,
+		collapsedTags:    make(map[string]bool),
+		inputHistory:     make(map[string][]string),
+		historyIndex:     -1,
+		keys:             keys,
+		lastActivity:     now,
+	}
+}
+
+// sendKeys runs Update with each key in turn. Keys are named as in the
+// key map ("enter", "tab", "down", ...); anything else is typed as runes.
+func sendKeys(m Model, keys ...string) Model {
+	named := map[string]tea.KeyType{
+		"enter":     tea.KeyEnter,
+		"esc":       tea.KeyEsc,
+		"tab":       tea.KeyTab,
+		"shift+tab": tea.KeyShiftTab,
+		"up":        tea.KeyUp,
+		"down":      tea.KeyDown,
+		"left":      tea.KeyLeft,
+		"right":     tea.KeyRight,
+		"backspace": tea.KeyBackspace,
+	}
+	for _, key := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		if keyType, ok := named[key]; ok {
+			msg = tea.KeyMsg{Type: keyType}
+		}
+		next, _ := m.Update(msg)
+		m = next.(Model)
+	}
+	return m
+}
+
+// closedEntry returns a finished entry from start lasting d.
+func closedEntry(start time.Time, d time.Duration, text string) storage.Entry {
+	end := start.Add(d)
+	return storage.Entry{Start: start, End: &end, Text: text}
+}
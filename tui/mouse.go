@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"lazytime/tui/components"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// rect is a screen region in cells.
+type rect struct {
+	x, y, width, height int
+}
+
+// contains reports whether the cell (x, y) is inside r.
+func (r rect) contains(x, y int) bool {
+	return x >= r.x && x < r.x+r.width && y >= r.y && y < r.y+r.height
+}
+
+// screenRegions locates the clickable sections of the main view.
+type screenRegions struct {
+	tabs    rect
+	main    rect // Box of the main pane
+	heatmap rect // Box of the heatmap below the goals
+}
+
+// regions computes where renderMainView draws each section.
+func (m Model) regions() screenRegions {
+	l := m.layout()
+	frameWidth := BoxStyle.GetHorizontalBorderSize()
+	frameHeight := BoxStyle.GetVerticalBorderSize()
+
+	var regions screenRegions
+	contentY := 0
+	if !l.minimal {
+		tabsY := lipgloss.Height(renderHero(m, l)) + l.verticalSpacing
+		contentY = tabsY + 1
+		regions.tabs = rect{x: 0, y: tabsY, width: l.width, height: 1}
+	}
+
+	// Columns are boxes separated by a space; hidden ones take no room
+	x := 0
+	if l.leftWidth > 0 {
+		regions.main = rect{x: x, y: contentY, width: l.leftWidth + frameWidth, height: l.mainHeight + frameHeight}
+		x += l.leftWidth + frameWidth + 1
+	}
+	if l.middleWidth > 0 {
+		x += l.middleWidth + frameWidth + 1
+	}
+	if l.rightWidth > 0 && l.tagsHeight > 0 {
+		// The goals box grows with its content
+		goalsHeight := lipgloss.Height(renderGoals(m, l))
+		regions.heatmap = rect{x: x, y: contentY + goalsHeight, width: l.rightWidth + frameWidth, height: l.tagsHeight + frameHeight}
+	}
+	return regions
+}
+
+// boxContentPos converts screen position (x, y) to a position within the
+// content of a BoxStyle box drawn at r.
+func boxContentPos(r rect, x, y int) (int, int) {
+	left := BoxStyle.GetBorderLeftSize() + BoxStyle.GetPaddingLeft()
+	top := BoxStyle.GetBorderTopSize() + BoxStyle.GetPaddingTop()
+	return x - r.x - left, y - r.y - top
+}
+
+// handleMouse handles clicks and wheel scrolling in the main view.
+func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.showModal || msg.Action != tea.MouseActionPress {
+		return m, nil
+	}
+	regions := m.regions()
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp, tea.MouseButtonWheelDown:
+		if !regions.main.contains(msg.X, msg.Y) {
+			return m, nil
+		}
+		delta := 1
+		if msg.Button == tea.MouseButtonWheelUp {
+			delta = -1
+		}
+		m.scrollPane(delta)
+	case tea.MouseButtonLeft:
+		switch {
+		case regions.tabs.contains(msg.X, msg.Y):
+			if view, ok := components.TabAt(m.activeTab(), msg.X-regions.tabs.x, TabActive, TabInactive); ok {
+				m.setViewMode(ViewMode(view))
+			}
+		case regions.main.contains(msg.X, msg.Y):
+			_, line := boxContentPos(regions.main, msg.X, msg.Y)
+			m.clickMainPane(line)
+		case regions.heatmap.contains(msg.X, msg.Y):
+			x, y := boxContentPos(regions.heatmap, msg.X, msg.Y)
+			if daysAgo, ok := components.MonthHeatmapDayAt(m.layout().rightWidth, m.layout().tagsHeight, x, y); ok {
+				// Jump to the day in the Today view
+				m.setViewMode(ViewToday)
+				m.rangeOffset = -daysAgo
+				m.clampSelection()
+			}
+		}
+	}
+	return m, nil
+}
+
+// clickMainPane handles a click on content line line of the main pane:
+// selecting an entry in the list or filtering by a tag in the tag tree.
+func (m *Model) clickMainPane(line int) {
+	switch m.pane {
+	case PaneList:
+		rows := m.listRows()
+		pos := m.listScrollOffset(rows) + line
+		if line < 0 || line >= m.listVisibleLines() || pos >= len(rows) || rows[pos].entryIndex < 0 {
+			return
+		}
+		m.selected = rows[pos].entryIndex
+	case PaneTree:
+		startUTC, endUTC := m.viewRange()
+		groups := m.tagTree(startUTC, endUTC)
+		index := components.TreeGroupAt(groups, m.layout().mainHeight, m.collapsedTags, m.treeCursor, line)
+		if index < 0 {
+			return
+		}
+		tag := components.VisibleTagGroups(groups, m.collapsedTags)[index].Tag
+		m.setTagFilter(tag)
+		m.setMessage("Filter: #"+tag, false)
+	}
+}
+
+// listVisibleLines returns the number of entry list lines shown at once.
+func (m Model) listVisibleLines() int {
+	return max(1, m.layout().mainHeight-2)
+}
+
+// listScrollOffset returns the scroll offset of the entry list as drawn by
+// renderEntryList, which clamps it to the rows.
+func (m Model) listScrollOffset(rows []listRow) int {
+	return min(m.scrollOffset, max(0, len(rows)-m.listVisibleLines()))
+}
+
+// scrollList scrolls the entry list by delta lines without moving the
+// selection.
+func (m *Model) scrollList(delta int) {
+	rows := m.listRows()
+	m.scrollOffset = max(0, m.listScrollOffset(rows)+delta)
+	m.scrollOffset = m.listScrollOffset(rows)
+}
+
+// scrollPane scrolls the main pane by delta lines for the mouse wheel: the
+// list and timeline scroll, the other panes move their cursor.
+func (m *Model) scrollPane(delta int) {
+	switch m.pane {
+	case PaneList:
+		m.scrollList(delta)
+	case PaneTimeline:
+		m.scrollTimeline(delta)
+	case PaneTree:
+		startUTC, endUTC := m.viewRange()
+		visible := components.VisibleTagGroups(m.tagTree(startUTC, endUTC), m.collapsedTags)
+		m.treeCursor = min(max(0, m.treeCursor+delta), max(0, len(visible)-1))
+	case PaneGaps:
+		m.gapCursor = min(max(0, m.gapCursor+delta), max(0, len(m.gaps())-1))
+	case PaneHeatmap:
+		// Scrolling down moves to later days
+		m.moveHeatmapCursor(-delta)
+	}
+}
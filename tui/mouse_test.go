@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"lazytime/tui/components"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// mouseModel returns a model with 48 ten-minute entries today, more than
+// the entry list shows at once.
+func mouseModel(t *testing.T) Model {
+	tz := time.FixedZone("UTC+1", 3600)
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, tz)
+	m := testModel(t, time.Date(2024, 3, 1, 18, 0, 0, 0, tz))
+	for i := range 48 {
+		m.entries = append(m.entries, closedEntry(start.Add(time.Duration(i)*10*time.Minute), 10*time.Minute, fmt.Sprintf("Task %02d #work/t%d", i, i%2)))
+	}
+	m.rawEntries = m.entries
+	return m
+}
+
+// screenPos returns the cell where text first appears on line y of the
+// view, or on any line for y < 0.
+func screenPos(t *testing.T, m Model, text string, y int) (int, int) {
+	t.Helper()
+	for i, line := range strings.Split(ansi.Strip(m.View()), "\n") {
+		if y >= 0 && i != y {
+			continue
+		}
+		if x := strings.Index(line, text); x >= 0 {
+			return ansi.StringWidth(line[:x]), i
+		}
+	}
+	t.Fatalf("%q not found in the view", text)
+	return 0, 0
+}
+
+func mouse(m Model, x, y int, button tea.MouseButton) Model {
+	next, _ := m.Update(tea.MouseMsg{X: x, Y: y, Button: button, Action: tea.MouseActionPress})
+	return next.(Model)
+}
+
+func TestClickTabSwitchesView(t *testing.T) {
+	m := mouseModel(t)
+	tabsY := m.regions().tabs.y
+	for _, tt := range []struct {
+		tab  string
+		want ViewMode
+	}{
+		{"Month", ViewMonth},
+		{"Year", ViewYear},
+		{"Today", ViewToday},
+	} {
+		x, y := screenPos(t, m, tt.tab, tabsY)
+		if m = mouse(m, x+1, y, tea.MouseButtonLeft); m.viewMode != tt.want {
+			t.Errorf("Clicking %s: expected view %v, got %v", tt.tab, tt.want, m.viewMode)
+		}
+	}
+}
+
+func TestClickEntrySelectsIt(t *testing.T) {
+	m := mouseModel(t)
+	// The list shows the latest entries first
+	x, y := screenPos(t, m, "Task 45", -1)
+	if m = mouse(m, x, y, tea.MouseButtonLeft); m.selected != 45 {
+		t.Errorf("Expected entry 45 selected, got %d", m.selected)
+	}
+
+	// Clicks outside the boxes change nothing
+	if m = mouse(m, m.width+5, y, tea.MouseButtonLeft); m.selected != 45 {
+		t.Errorf("Expected the selection to stay, got %d", m.selected)
+	}
+
+	// Clicks are ignored while a modal is open
+	m = sendKeys(m, "/")
+	if m = mouse(m, x, y+1, tea.MouseButtonLeft); m.selected != 45 {
+		t.Errorf("Expected the click behind the modal to be ignored, got %d", m.selected)
+	}
+}
+
+func TestWheelScrollsEntryList(t *testing.T) {
+	m := mouseModel(t)
+	x, y := screenPos(t, m, "Task 47", -1)
+	m = mouse(m, x, y, tea.MouseButtonWheelDown)
+	m = mouse(m, x, y, tea.MouseButtonWheelDown)
+	if m.scrollOffset != 2 || m.selected != -1 {
+		t.Fatalf("Expected the list scrolled by 2 without a selection, got offset %d selected %d", m.scrollOffset, m.selected)
+	}
+
+	// The row under the same spot moved up by two entries
+	if m = mouse(m, x, y, tea.MouseButtonLeft); m.selected != 45 {
+		t.Errorf("Expected entry 45 under the cursor after scrolling, got %d", m.selected)
+	}
+	m = mouse(m, x, y, tea.MouseButtonWheelUp)
+	if m.scrollOffset != 1 {
+		t.Errorf("Expected wheel up to scroll back, got offset %d", m.scrollOffset)
+	}
+}
+
+func TestClickTreeTagFilters(t *testing.T) {
+	m := sendKeys(mouseModel(t), "t")
+	x, y := screenPos(t, m, "t1", -1)
+	m = mouse(m, x, y, tea.MouseButtonLeft)
+	if m.tagFilter != "work/t1" {
+		t.Errorf("Expected the filter work/t1, got %q", m.tagFilter)
+	}
+}
+
+func TestClickHeatmapDayOpensIt(t *testing.T) {
+	m := sendKeys(mouseModel(t), "2")
+	r := m.regions().heatmap
+	if r.width == 0 {
+		t.Fatal("Expected the sidebar heatmap at the default size")
+	}
+	l := m.layout()
+	for y := r.y; y < r.y+r.height; y++ {
+		for x := r.x; x < r.x+r.width; x++ {
+			cx, cy := boxContentPos(r, x, y)
+			if daysAgo, ok := components.MonthHeatmapDayAt(l.rightWidth, l.tagsHeight, cx, cy); ok && daysAgo > 0 {
+				m = mouse(m, x, y, tea.MouseButtonLeft)
+				if m.viewMode != ViewToday || m.rangeOffset != -daysAgo {
+					t.Errorf("Expected the Today view %d days back, got view %v offset %d", daysAgo, m.viewMode, m.rangeOffset)
+				}
+				return
+			}
+		}
+	}
+	t.Fatal("Expected a past day in the heatmap")
+}
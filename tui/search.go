@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"lazytime/storage"
+	"lazytime/tui/components"
+)
+
+// searchEntries returns the indices of entries whose text (including tags)
+// fuzzy-matches every word of query, most recent first.
+func searchEntries(entries []storage.Entry, query string) []int {
+	terms := strings.Fields(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	var results []int
+	for i, entry := range entries {
+		matched := true
+		for _, term := range terms {
+			if !components.FuzzyMatch(strings.TrimPrefix(term, "#"), entry.Text) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			results = append(results, i)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return entries[results[i]].Start.After(entries[results[j]].Start)
+	})
+	return results
+}
+
+// searchResults converts matching entries for components.RenderSearchModal.
+func (m Model) searchResults() []components.SearchResult {
+	results := make([]components.SearchResult, len(m.searchMatches))
+	for i, idx := range m.searchMatches {
+		entry := m.entries[idx]
+		end := m.now
+		if entry.End != nil {
+			end = *entry.End
+		}
+		results[i] = components.SearchResult{
+			Text:     entry.Text,
+			Start:    entry.Start,
+			End:      end,
+			Running:  entry.End == nil,
+			Duration: end.Sub(entry.Start),
+		}
+	}
+	return results
+}
+
+// jumpToEntry shows the day of the entry at idx in the Today view and
+// selects the entry.
+func (m *Model) jumpToEntry(idx int) {
+	tz := m.now.Location()
+	startLocal := m.entries[idx].Start.In(tz)
+	entryDay := time.Date(startLocal.Year(), startLocal.Month(), startLocal.Day(), 0, 0, 0, 0, tz)
+	today := time.Date(m.now.Year(), m.now.Month(), m.now.Day(), 0, 0, 0, 0, tz)
+
+	m.setViewMode(ViewToday)
+	m.pane = PaneList
+	m.rangeOffset = int(math.Round(entryDay.Sub(today).Hours() / 24))
+	m.selected = idx
+	m.moveSelection(0) // Scroll the entry into view
+}
@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func searchModel(t *testing.T) Model {
+	tz := time.FixedZone("UTC+2", 2*3600)
+	return testModel(t, time.Date(2024, 3, 1, 18, 0, 0, 0, tz),
+		closedEntry(time.Date(2024, 2, 28, 9, 0, 0, 0, tz), time.Hour, "Fix API bug #clientA/api"),
+		closedEntry(time.Date(2024, 3, 1, 9, 0, 0, 0, tz), time.Hour, "API review #clientA"),
+		closedEntry(time.Date(2024, 3, 1, 12, 0, 0, 0, tz), time.Hour, "Lunch"),
+	)
+}
+
+func TestSearchEntries(t *testing.T) {
+	entries := searchModel(t).entries
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"api", []int{1, 0}}, // Most recent first
+		{"#api", []int{1, 0}},
+		{"fix api", []int{0}},
+		{"clienta/api", []int{0}},
+		{"review bug", nil},
+		{"  ", nil},
+	}
+	for _, tt := range tests {
+		got := searchEntries(entries, tt.query)
+		if len(got) != len(tt.want) {
+			t.Errorf("searchEntries(%q) = %v, want %v", tt.query, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("searchEntries(%q) = %v, want %v", tt.query, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestSearchJumpsToSelectedEntry(t *testing.T) {
+	m := sendKeys(searchModel(t), "/", "a", "p", "i")
+	if !m.showModal || m.modalType != "search" {
+		t.Fatalf("Expected the search modal, got showModal=%v modalType=%q", m.showModal, m.modalType)
+	}
+	view := ansi.Strip(m.View())
+	if review, bug := strings.Index(view, "API review"), strings.Index(view, "Fix API bug"); review < 0 || bug < review {
+		t.Errorf("Expected both matches, most recent first:\n%s", view)
+	}
+
+	// The second match is two days back (2024 is a leap year)
+	m = sendKeys(m, "down", "enter")
+	if m.showModal {
+		t.Fatalf("Expected the search modal to close, got %q", m.modalType)
+	}
+	if m.viewMode != ViewToday || m.pane != PaneList || m.rangeOffset != -2 || m.selected != 0 {
+		t.Errorf("Expected entry 0 selected two days back in the Today list, got view %v pane %v offset %d selected %d",
+			m.viewMode, m.pane, m.rangeOffset, m.selected)
+	}
+}
+
+func TestSearchWithoutMatchesStaysOpen(t *testing.T) {
+	m := sendKeys(searchModel(t), "/", "x", "y", "z", "enter")
+	if !m.showModal || m.modalType != "search" || len(m.searchMatches) != 0 {
+		t.Errorf("Expected the search modal to stay open without matches, got showModal=%v matches %v", m.showModal, m.searchMatches)
+	}
+}
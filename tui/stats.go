@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"lazytime/storage"
+	"lazytime/tui/components"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// statsTagLines is the number of tags listed in the stats pane.
+const statsTagLines = 6
+
+// sparklineDays is the number of days in the stats sparkline of the Today
+// view, ending with the day shown.
+const sparklineDays = 14
+
+// renderStats renders the stats pane for the active view: the tag
+// distribution, the tracked time per day (per month in the Year view)
+// stacked by tag, and a sparkline of the daily totals.
+func renderStats(m Model, width, height int) string {
+	startUTC, endUTC := m.viewRange()
+	entries := m.filteredEntries()
+	totals := CalculateTagTotals(entries, startUTC, endUTC, m.now, m.rounding)
+	if len(totals) == 0 {
+		return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, MutedStyle.Render(m.emptyListText()))
+	}
+
+	// Account for box padding (2 chars on each side = 4 total)
+	lineWidth := width - 4
+	header := TreeTagStyle.Copy().Bold(true)
+
+	var lines []string
+	lines = append(lines, header.Render("Tags"))
+	lines = append(lines, components.TagChartLines(totals, lineWidth, statsTagLines, ChartBarStyle, ChartLabelStyle, ChartPercentStyle, GetTagColor, FormatDurationShort)...)
+
+	// Daily totals, over the last days for the Today view
+	sparkStart := startUTC
+	if m.viewMode == ViewToday {
+		sparkStart = storage.ToUTC(startUTC.In(m.now.Location()).AddDate(0, 0, -(sparklineDays - 1)))
+	}
+	dailyTotals := DailyTotals(entries, sparkStart, endUTC, m.now)
+	var values []time.Duration
+	var sum, peak time.Duration
+	for _, day := range components.TimelineDays(sparkStart, endUTC, m.now) {
+		value := dailyTotals[day.Format("2006-01-02")]
+		values = append([]time.Duration{value}, values...) // Oldest first
+		sum += value
+		peak = max(peak, value)
+	}
+	if len(values) > lineWidth {
+		values = values[len(values)-lineWidth:]
+	}
+	sparkLines := []string{
+		"",
+		header.Render("Daily totals"),
+		ChartBarStyle.Render(components.RenderSparkline(values)),
+	}
+	if len(values) > 0 {
+		sparkLines = append(sparkLines, TreeDurationStyle.Render(fmt.Sprintf("avg %s/day  max %s", FormatDurationShort(sum/time.Duration(len(values))), FormatDurationShort(peak))))
+	}
+
+	// Stacked bars take the remaining height
+	if bars := m.statsBars(entries, totals); len(bars) > 0 {
+		title := "By day"
+		if m.viewMode == ViewYear {
+			title = "By month"
+		}
+		chartHeight := max(3, height-2-len(lines)-len(sparkLines)-3)
+		lines = append(lines, "", header.Render(title))
+		lines = append(lines, components.StackedBarLines(bars, lineWidth, chartHeight, TreeDurationStyle, GetTagColor)...)
+	}
+	lines = append(lines, sparkLines...)
+
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	return BoxStyle.Width(width).Height(height).Render(content)
+}
+
+// statsBars returns the stacked bars of the stats pane: a bar per day for
+// the Week and Month views and per month for the Year view, with each
+// entry's time counted toward its first tag (as in the timeline), spelled
+// as in totals. Tags are stacked in the order of their totals. The Today
+// view has no bars.
+func (m Model) statsBars(entries []storage.Entry, totals map[string]time.Duration) []components.StackedBar {
+	startUTC, endUTC := m.viewRange()
+	tz := m.now.Location()
+
+	type period struct {
+		start, end time.Time
+		label      string
+	}
+	var periods []period
+	switch m.viewMode {
+	case ViewWeek, ViewMonth:
+		for day := startUTC.In(tz); day.Before(endUTC); day = day.AddDate(0, 0, 1) {
+			label := day.Format("2")
+			if m.viewMode == ViewWeek {
+				label = day.Format("Mon")
+			}
+			periods = append(periods, period{day, day.AddDate(0, 0, 1), label})
+		}
+	case ViewYear:
+		for month := startUTC.In(tz); month.Before(endUTC); month = month.AddDate(0, 1, 0) {
+			periods = append(periods, period{month, month.AddDate(0, 1, 0), month.Format("Jan")})
+		}
+	default:
+		return nil
+	}
+
+	// Stack the largest tags at the bottom
+	rank := make(map[string]int)
+	names := make(map[string]string) // Tag key -> spelling in totals
+	var tags []string
+	for tag := range totals {
+		tags = append(tags, tag)
+		names[storage.TagKey(tag)] = tag
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if totals[tags[i]] != totals[tags[j]] {
+			return totals[tags[i]] > totals[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+	for i, tag := range tags {
+		rank[tag] = i
+	}
+
+	bars := make([]components.StackedBar, len(periods))
+	for i, p := range periods {
+		byTag := make(map[string]time.Duration)
+		for _, entry := range entries {
+			duration := clampDuration(entry, storage.ToUTC(p.start), storage.ToUTC(p.end), m.now)
+			if duration <= 0 {
+				continue
+			}
+			tag := ""
+			if entryTags := entry.Tags(); len(entryTags) > 0 {
+				tag = names[storage.TagKey(entryTags[0])]
+			}
+			byTag[tag] += duration
+		}
+		bars[i].Label = p.label
+		for tag, duration := range byTag {
+			bars[i].Segments = append(bars[i].Segments, components.StackSegment{Tag: tag, Duration: duration})
+		}
+		sort.Slice(bars[i].Segments, func(a, b int) bool {
+			rankA, okA := rank[bars[i].Segments[a].Tag]
+			rankB, okB := rank[bars[i].Segments[b].Tag]
+			if okA != okB {
+				return okA // Untagged time on top
+			}
+			if rankA != rankB {
+				return rankA < rankB
+			}
+			return bars[i].Segments[a].Tag < bars[i].Segments[b].Tag
+		})
+	}
+	return bars
+}
@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"lazytime/storage"
+)
+
+func TestTagTotalsTreeAndStatsFoldCase(t *testing.T) {
+	tz := time.FixedZone("UTC+1", 3600)
+	at := func(day, hour int) time.Time {
+		return time.Date(2024, 2, day, hour, 0, 0, 0, tz)
+	}
+	// Monday the 26th and Tuesday the 27th
+	m := testModel(t, at(27, 18),
+		closedEntry(at(26, 9), 2*time.Hour, "Build #API"),
+		closedEntry(at(27, 9), time.Hour, "Fix #api"),
+		closedEntry(at(27, 10), time.Hour, "Review #Api #api"),
+		closedEntry(at(27, 11), 30*time.Minute, "Docs #docs"),
+	)
+	m.setViewMode(ViewWeek)
+	startUTC, endUTC := m.viewRange()
+
+	totals := CalculateTagTotals(m.entries, startUTC, endUTC, m.now, storage.Rounding{})
+	if len(totals) != 2 || totals["API"] != 4*time.Hour || totals["docs"] != 30*time.Minute {
+		t.Errorf("Expected API 4h and docs 30m in the first seen spelling, got %v", totals)
+	}
+
+	groups := GroupByTag(m.entries, startUTC, endUTC, m.now, storage.Rounding{})
+	if len(groups) != 2 || groups[0].Tag != "API" || groups[0].Duration != 4*time.Hour || len(groups[0].Entries) != 3 {
+		t.Errorf("Expected one API group of 4h over 3 entries, got %+v", groups)
+	}
+
+	bars := m.statsBars(m.entries, totals)
+	if len(bars) != 7 {
+		t.Fatalf("Expected a bar per weekday, got %d", len(bars))
+	}
+	monday, tuesday := bars[0].Segments, bars[1].Segments
+	if len(monday) != 1 || monday[0].Tag != "API" || monday[0].Duration != 2*time.Hour {
+		t.Errorf("Expected Monday's 2h under API, got %+v", monday)
+	}
+	if len(tuesday) != 2 || tuesday[0].Tag != "API" || tuesday[0].Duration != 2*time.Hour || tuesday[1].Tag != "docs" {
+		t.Errorf("Expected Tuesday's 2h under API below docs, got %+v", tuesday)
+	}
+}
@@ -6,108 +6,173 @@ import (
 	"strings"
 	"time"
 
+	"lazytime/tui/components"
+
 	"github.com/charmbracelet/lipgloss"
 )
 
-// Color palette for tags (16 colors that work well in terminals)
-var tagColorPalette = []string{
-	"#00ff00", // green
-	"#00ffff", // cyan
-	"#ff00ff", // magenta
-	"#ffff00", // yellow
-	"#ff8800", // orange
-	"#0088ff", // blue
-	"#ff0088", // pink
-	"#88ff00", // lime
-	"#00ff88", // spring green
-	"#8800ff", // purple
-	"#ff8800", // orange
-	"#0088ff", // light blue
-	"#ff0088", // hot pink
-	"#88ff00", // chartreuse
-	"#00ff88", // aquamarine
-	"#8800ff", // violet
-}
+// Color palette for tags, set from the active theme
+var tagColorPalette []string
 
-// Style definitions
+// Style definitions, set from the active theme by applyTheme
 var (
 	// Status colors
-	StyleRunning = lipgloss.NewStyle().Foreground(lipgloss.Color("#00ff00")).Bold(true)
-	StyleIdle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
-	StylePaused  = lipgloss.NewStyle().Foreground(lipgloss.Color("#ffff00"))
+	StyleRunning lipgloss.Style
+	StyleIdle    lipgloss.Style
+	StylePaused  lipgloss.Style
 
 	// Border styles
-	BorderRunning = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#00ff00")).
-			Padding(1, 2)
-	BorderIdle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#ffff00")).
-			Padding(1, 2)
-	BorderPaused = lipgloss.NewStyle().
+	BorderRunning lipgloss.Style
+	BorderIdle    lipgloss.Style
+	BorderPaused  lipgloss.Style
+
+	// Hero section
+	HeroTimerStyle lipgloss.Style
+	HeroTaskStyle  lipgloss.Style
+	HeroTagStyle   lipgloss.Style
+
+	// Progress bars
+	ProgressOnTrack lipgloss.Style
+	ProgressBehind  lipgloss.Style
+	ProgressAhead   lipgloss.Style
+
+	// Tabs
+	TabActive   lipgloss.Style
+	TabInactive lipgloss.Style
+
+	// Tree view
+	TreeTagStyle      lipgloss.Style
+	TreeTaskStyle     lipgloss.Style
+	TreeDurationStyle lipgloss.Style
+
+	// Selection highlight (tree cursor, selected rows)
+	SelectedStyle lipgloss.Style
+
+	// Text cursor in modal inputs
+	CursorStyle lipgloss.Style
+
+	// Timeline
+	TimelineGapStyle  lipgloss.Style
+	TimelineIdleStyle lipgloss.Style
+
+	// Charts
+	ChartBarStyle     lipgloss.Style
+	ChartLabelStyle   lipgloss.Style
+	ChartPercentStyle lipgloss.Style
+
+	// Box styles
+	BoxStyle lipgloss.Style
+
+	// Footer
+	FooterStyle lipgloss.Style
+
+	// Keys in the help modal
+	HelpKeyStyle lipgloss.Style
+
+	// Placeholder text and the main view dimmed behind modals
+	MutedStyle  lipgloss.Style
+	DimmedStyle lipgloss.Style
+
+	// Error/Success messages
+	ErrorStyle   lipgloss.Style
+	SuccessStyle lipgloss.Style
+	WarningStyle lipgloss.Style
+)
+
+func init() {
+	applyTheme(darkTheme)
+}
+
+// applyTheme sets the styles, the tag palette and the component colors
+// from theme.
+func applyTheme(theme Theme) {
+	color := func(hex string) lipgloss.Color { return lipgloss.Color(hex) }
+	box := func(border string) lipgloss.Style {
+		return lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#ffff00")).
+			BorderForeground(color(border)).
 			Padding(1, 2)
+	}
+
+	tagColorPalette = theme.TagColors
+
+	StyleRunning = lipgloss.NewStyle().Foreground(color(theme.Running)).Bold(true)
+	StyleIdle = lipgloss.NewStyle().Foreground(color(theme.Muted))
+	StylePaused = lipgloss.NewStyle().Foreground(color(theme.Idle))
+
+	BorderRunning = box(theme.Running)
+	BorderIdle = box(theme.Idle)
+	BorderPaused = box(theme.Idle)
 
-	// Hero section
 	HeroTimerStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#00ff00"))
+		Bold(true).
+		Foreground(color(theme.Running))
 	HeroTaskStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#ffffff"))
+		Foreground(color(theme.Text))
 	HeroTagStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
-			Italic(true)
+		Foreground(color(theme.Muted)).
+		Italic(true)
 
-	// Progress bars
-	ProgressOnTrack = lipgloss.NewStyle().Foreground(lipgloss.Color("#00ff00"))
-	ProgressBehind  = lipgloss.NewStyle().Foreground(lipgloss.Color("#ffaa00"))
-	ProgressAhead   = lipgloss.NewStyle().Foreground(lipgloss.Color("#0088ff"))
+	ProgressOnTrack = lipgloss.NewStyle().Foreground(color(theme.Running))
+	ProgressBehind = lipgloss.NewStyle().Foreground(color(theme.Warning))
+	ProgressAhead = lipgloss.NewStyle().Foreground(color(theme.Accent))
 
-	// Tabs
 	TabActive = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#ffffff")).
-			Background(lipgloss.Color("#0088ff")).
-			Padding(0, 2).
-			Bold(true)
+		Foreground(color(theme.AccentText)).
+		Background(color(theme.Accent)).
+		Padding(0, 2).
+		Bold(true)
 	TabInactive = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
-			Padding(0, 2)
+		Foreground(color(theme.Muted)).
+		Padding(0, 2)
 
-	// Tree view
 	TreeTagStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#ffffff")).
-			Bold(true)
+		Foreground(color(theme.Text)).
+		Bold(true)
 	TreeTaskStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#cccccc"))
+		Foreground(color(theme.SubtleText))
 	TreeDurationStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#888888"))
+		Foreground(color(theme.Muted))
+
+	SelectedStyle = lipgloss.NewStyle().
+		Background(color(theme.Selection)).
+		Bold(true)
+
+	CursorStyle = lipgloss.NewStyle().Reverse(true)
+
+	TimelineGapStyle = lipgloss.NewStyle().Foreground(color(theme.Warning))
+	TimelineIdleStyle = lipgloss.NewStyle().Foreground(color(theme.Faint))
 
-	// Charts
 	ChartBarStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#0088ff"))
+		Foreground(color(theme.Accent))
 	ChartLabelStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#ffffff"))
+		Foreground(color(theme.Text))
 	ChartPercentStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#888888"))
+		Foreground(color(theme.Muted))
 
-	// Box styles
-	BoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#444444")).
-			Padding(1, 2)
+	BoxStyle = box(theme.Faint)
 
-	// Footer
 	FooterStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")).
-			Italic(true)
+		Foreground(color(theme.Muted)).
+		Italic(true)
 
-	// Error/Success messages
-	ErrorStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff0000"))
-	SuccessStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#00ff00"))
-	WarningStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#ffaa00"))
-)
+	HelpKeyStyle = lipgloss.NewStyle().Foreground(color(theme.Accent))
+
+	MutedStyle = lipgloss.NewStyle().Foreground(color(theme.Muted))
+	DimmedStyle = lipgloss.NewStyle().Foreground(color(theme.Faint))
+
+	ErrorStyle = lipgloss.NewStyle().Foreground(color(theme.Error))
+	SuccessStyle = lipgloss.NewStyle().Foreground(color(theme.Running))
+	WarningStyle = lipgloss.NewStyle().Foreground(color(theme.Warning))
+
+	components.MutedColor = color(theme.Muted)
+	components.LabelColor = color(theme.Text)
+	components.ModalBorderColor = color(theme.Running)
+	components.HeatmapColors = make([]lipgloss.Color, len(theme.Heatmap))
+	for i, hex := range theme.Heatmap {
+		components.HeatmapColors[i] = color(hex)
+	}
+}
 
 // GetTagColor returns a consistent color for a tag.
 func GetTagColor(tag string) lipgloss.Color {
@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"lazytime/storage"
+	"lazytime/tui/components"
+)
+
+// Kinds of suggestions shown below a modal input.
+const (
+	suggestTags = "tag"  // Tag names (without "#")
+	suggestText = "text" // Full entry texts
+)
+
+// timeOverridePattern matches the @time overrides of the new entry modal.
+var timeOverridePattern = regexp.MustCompile(`@(?:\d{4}-\d{2}-\d{2}T)?\d{1,2}:\d{2}`)
+
+// textUsage tracks how often and how recently an entry text was used.
+type textUsage struct {
+	text     string
+	score    float64
+	lastUsed time.Time
+}
+
+// textSuggestions returns past entry texts fuzzy-matching query, ranked by
+// frecency: every use counts, recent uses count more.
+func textSuggestions(entries []storage.Entry, query string, now time.Time, limit int) []string {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+
+	usages := make(map[string]*textUsage)
+	for _, entry := range entries {
+		text := strings.TrimSpace(entry.Text)
+		if text == "" || strings.EqualFold(text, query) || !components.FuzzyMatch(query, text) {
+			continue
+		}
+		key := strings.ToLower(text)
+		usage, exists := usages[key]
+		if !exists {
+			usage = &textUsage{text: text}
+			usages[key] = usage
+		}
+		// A use a week ago counts half as much as one today
+		ageDays := now.Sub(entry.Start).Hours() / 24
+		usage.score += 1 / (1 + max(0, ageDays)/7)
+		if entry.Start.After(usage.lastUsed) {
+			usage.lastUsed = entry.Start
+			usage.text = text // Prefer the most recent spelling
+		}
+	}
+
+	ranked := make([]*textUsage, 0, len(usages))
+	for _, usage := range usages {
+		ranked = append(ranked, usage)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].lastUsed.After(ranked[j].lastUsed)
+	})
+
+	var texts []string
+	for _, usage := range ranked {
+		if len(texts) >= limit {
+			break
+		}
+		texts = append(texts, usage.text)
+	}
+	return texts
+}
+
+// completeTag replaces the tag being typed at the end of input with tag.
+func completeTag(input, tag string) string {
+	lastHash := strings.LastIndex(input, "#")
+	if lastHash == -1 {
+		return input
+	}
+	return input[:lastHash] + "#" + tag + " "
+}
+
+// completeText replaces input with text, keeping any @time overrides.
+func completeText(input, text string) string {
+	overrides := timeOverridePattern.FindAllString(input, -1)
+	if len(overrides) == 0 {
+		return text + " "
+	}
+	return strings.Join(overrides, " ") + " " + text + " "
+}
+
+// textQuery returns the input without @time overrides, for matching
+// against past entry texts.
+func textQuery(input string) string {
+	return strings.Join(strings.Fields(timeOverridePattern.ReplaceAllString(input, "")), " ")
+}
@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"testing"
+	"time"
+)
+
+func suggestionModel(t *testing.T) Model {
+	now := time.Date(2024, 3, 1, 18, 0, 0, 0, time.FixedZone("UTC+1", 3600))
+	day := func(daysAgo int) time.Time {
+		return now.Add(-time.Duration(daysAgo)*24*time.Hour - 8*time.Hour)
+	}
+	return testModel(t, now,
+		closedEntry(day(7), time.Hour, "Write report #clientA"),
+		closedEntry(day(6), time.Hour, "Write report #clientA"),
+		closedEntry(day(5), time.Hour, "Write report #clientA"),
+		closedEntry(day(1), time.Hour, "Write docs #docs"),
+		closedEntry(day(0), time.Hour, "Review PR #clientA/api"),
+	)
+}
+
+func TestTextSuggestionsRankByFrecency(t *testing.T) {
+	m := suggestionModel(t)
+	got := textSuggestions(m.entries, "write", m.now, 5)
+	// Three uses last week outweigh one use yesterday
+	if len(got) != 2 || got[0] != "Write report #clientA" || got[1] != "Write docs #docs" {
+		t.Errorf("Expected the report then the docs, got %v", got)
+	}
+	if got := textSuggestions(m.entries, "write docs #docs", m.now, 5); len(got) != 0 {
+		t.Errorf("Expected no suggestion for a text typed in full, got %v", got)
+	}
+}
+
+func TestTabCompletesTextSuggestion(t *testing.T) {
+	m := sendKeys(suggestionModel(t), "n", "@09:00 wri")
+	if m.suggestionKind != suggestText || len(m.modalSuggestions) != 2 {
+		t.Fatalf("Expected two text suggestions, got %q %v", m.suggestionKind, m.modalSuggestions)
+	}
+	m = sendKeys(m, "down", "tab")
+	if want := "@09:00 Write docs #docs "; m.modalInput != want {
+		t.Errorf("Expected %q, got %q", want, m.modalInput)
+	}
+}
+
+func TestTabCompletesTagAtCursor(t *testing.T) {
+	m := sendKeys(suggestionModel(t), "n", "Fix #cli bug", "left", "left", "left", "left")
+	if m.suggestionKind != suggestTags || len(m.modalSuggestions) == 0 || m.modalSuggestions[0] != "clienta" {
+		t.Fatalf("Expected tag suggestions for #cli, got %q %v", m.suggestionKind, m.modalSuggestions)
+	}
+	m = sendKeys(m, "tab")
+	if want := "Fix #clienta bug"; m.modalInput != want {
+		t.Errorf("Expected %q, got %q", want, m.modalInput)
+	}
+	if want := len([]rune("Fix #clienta ")); m.modalCursor != want {
+		t.Errorf("Expected the cursor after the completed tag at %d, got %d", want, m.modalCursor)
+	}
+}
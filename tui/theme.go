@@ -0,0 +1,241 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme holds the colors (hex, e.g. "#ff8800") all TUI styles are built
+// from. Theme files are JSON with these keys plus "base", the built-in
+// theme whose colors are used for keys the file leaves out.
+type Theme struct {
+	Text       string   `json:"text"`        // Main text: hero task, tree tags, chart labels
+	SubtleText string   `json:"subtle_text"` // Task names in the tree
+	Muted      string   `json:"muted"`       // Durations, inactive tabs, footer, placeholders
+	Faint      string   `json:"faint"`       // Box borders, idle timeline cells, dimmed view
+	Selection  string   `json:"selection"`   // Background of the selected row
+	Accent     string   `json:"accent"`      // Active tab, chart bars, goals ahead
+	AccentText string   `json:"accent_text"` // Text on the accent color
+	Running    string   `json:"running"`     // Running entry, success messages, modal borders
+	Idle       string   `json:"idle"`        // Idle hero border
+	Warning    string   `json:"warning"`     // Gaps, goals behind
+	Error      string   `json:"error"`       // Errors and overlaps
+	Heatmap    []string `json:"heatmap"`     // Heatmap levels, none to most (5 colors)
+	TagColors  []string `json:"tag_colors"`  // Palette tags are hashed into
+}
+
+// Built-in themes by name
+var (
+	darkTheme = Theme{
+		Text:       "#ffffff",
+		SubtleText: "#cccccc",
+		Muted:      "#888888",
+		Faint:      "#444444",
+		Selection:  "#333333",
+		Accent:     "#0088ff",
+		AccentText: "#ffffff",
+		Running:    "#00ff00",
+		Idle:       "#ffff00",
+		Warning:    "#ffaa00",
+		Error:      "#ff0000",
+		Heatmap:    []string{"#333333", "#005500", "#00aa00", "#00ff00", "#88ff88"},
+		TagColors: []string{
+			"#00ff00", // green
+			"#00ffff", // cyan
+			"#ff00ff", // magenta
+			"#ffff00", // yellow
+			"#ff8800", // orange
+			"#0088ff", // blue
+			"#ff0088", // pink
+			"#88ff00", // lime
+			"#00ff88", // spring green
+			"#8800ff", // purple
+			"#ff8800", // orange
+			"#0088ff", // light blue
+			"#ff0088", // hot pink
+			"#88ff00", // chartreuse
+			"#00ff88", // aquamarine
+			"#8800ff", // violet
+		},
+	}
+
+	lightTheme = Theme{
+		Text:       "#1a1a1a",
+		SubtleText: "#3a3a3a",
+		Muted:      "#6c6c6c",
+		Faint:      "#b2b2b2",
+		Selection:  "#dadada",
+		Accent:     "#005fd7",
+		AccentText: "#ffffff",
+		Running:    "#008700",
+		Idle:       "#af8700",
+		Warning:    "#d75f00",
+		Error:      "#d70000",
+		Heatmap:    []string{"#e4e4e4", "#9be9a8", "#40c463", "#30a14e", "#216e39"},
+		TagColors: []string{
+			"#008700", "#0087af", "#af00af", "#af8700",
+			"#d75f00", "#005fd7", "#d7005f", "#5f8700",
+			"#00875f", "#5f00d7", "#875f00", "#005f87",
+		},
+	}
+
+	highContrastTheme = Theme{
+		Text:       "#ffffff",
+		SubtleText: "#ffffff",
+		Muted:      "#d0d0d0",
+		Faint:      "#a8a8a8",
+		Selection:  "#0000af",
+		Accent:     "#ffff00",
+		AccentText: "#000000",
+		Running:    "#00ff00",
+		Idle:       "#ffff00",
+		Warning:    "#ff8700",
+		Error:      "#ff5f5f",
+		Heatmap:    []string{"#3a3a3a", "#0087ff", "#00d7ff", "#87ffff", "#ffffff"},
+		TagColors: []string{
+			"#00ff00", "#00ffff", "#ff00ff", "#ffff00",
+			"#ff8700", "#5fafff", "#ff5faf", "#ffffff",
+		},
+	}
+
+	// Okabe-Ito colors, distinguishable with the common color vision
+	// deficiencies; the heatmap uses viridis
+	colorblindTheme = Theme{
+		Text:       "#ffffff",
+		SubtleText: "#cccccc",
+		Muted:      "#999999",
+		Faint:      "#555555",
+		Selection:  "#333333",
+		Accent:     "#0072b2",
+		AccentText: "#ffffff",
+		Running:    "#56b4e9",
+		Idle:       "#f0e442",
+		Warning:    "#e69f00",
+		Error:      "#d55e00",
+		Heatmap:    []string{"#333333", "#3b528b", "#21918c", "#5ec962", "#fde725"},
+		TagColors: []string{
+			"#e69f00", "#56b4e9", "#009e73", "#f0e442",
+			"#0072b2", "#d55e00", "#cc79a7", "#bbbbbb",
+		},
+	}
+
+	themes = map[string]Theme{
+		"dark":          darkTheme,
+		"light":         lightTheme,
+		"high-contrast": highContrastTheme,
+		"colorblind":    colorblindTheme,
+	}
+)
+
+// loadTheme returns the theme named name: "auto" (or empty) picks dark or
+// light from the terminal background, a built-in theme name selects it,
+// and anything else is read as a theme file, relative to configDir.
+func loadTheme(name, configDir string) (Theme, error) {
+	switch name {
+	case "", "auto":
+		if lipgloss.HasDarkBackground() {
+			return darkTheme, nil
+		}
+		return lightTheme, nil
+	}
+	if theme, ok := themes[name]; ok {
+		return theme, nil
+	}
+	if !strings.HasSuffix(name, ".json") {
+		return darkTheme, fmt.Errorf("unknown theme %q (use auto, %s or a .json theme file)", name, strings.Join(themeNames(), ", "))
+	}
+	return readThemeFile(name, configDir)
+}
+
+// themeNames returns the names of the built-in themes, sorted.
+func themeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// readThemeFile reads a theme file. Keys it leaves out keep the colors of
+// its "base" theme (dark by default).
+func readThemeFile(path, configDir string) (Theme, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(configDir, path)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return darkTheme, fmt.Errorf("failed to read theme file: %w", err)
+	}
+
+	var header struct {
+		Base string `json:"base"`
+	}
+	if err := json.Unmarshal(content, &header); err != nil {
+		return darkTheme, fmt.Errorf("invalid theme file %s: %w", path, err)
+	}
+	theme := darkTheme
+	if header.Base != "" {
+		base, ok := themes[header.Base]
+		if !ok {
+			return darkTheme, fmt.Errorf("invalid theme file %s: unknown base theme %q", path, header.Base)
+		}
+		theme = base
+	}
+
+	// Only the keys present in the file replace the base colors
+	if err := json.Unmarshal(content, &theme); err != nil {
+		return darkTheme, fmt.Errorf("invalid theme file %s: %w", path, err)
+	}
+	if len(theme.Heatmap) != 5 {
+		return darkTheme, fmt.Errorf("invalid theme file %s: heatmap needs 5 colors, got %d", path, len(theme.Heatmap))
+	}
+	if len(theme.TagColors) == 0 {
+		return darkTheme, fmt.Errorf("invalid theme file %s: tag_colors is empty", path)
+	}
+	if err := validateThemeColors(theme); err != nil {
+		return darkTheme, fmt.Errorf("invalid theme file %s: %w", path, err)
+	}
+	return theme, nil
+}
+
+// hexColorPattern matches the "#rrggbb" colors themes are written in.
+var hexColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// validateThemeColors checks that every color of the theme is a hex color,
+// so a typo is reported rather than rendered as an invisible style.
+func validateThemeColors(theme Theme) error {
+	colors := []struct {
+		key    string
+		values []string
+	}{
+		{"text", []string{theme.Text}},
+		{"subtle_text", []string{theme.SubtleText}},
+		{"muted", []string{theme.Muted}},
+		{"faint", []string{theme.Faint}},
+		{"selection", []string{theme.Selection}},
+		{"accent", []string{theme.Accent}},
+		{"accent_text", []string{theme.AccentText}},
+		{"running", []string{theme.Running}},
+		{"idle", []string{theme.Idle}},
+		{"warning", []string{theme.Warning}},
+		{"error", []string{theme.Error}},
+		{"heatmap", theme.Heatmap},
+		{"tag_colors", theme.TagColors},
+	}
+	for _, color := range colors {
+		for _, value := range color.values {
+			if !hexColorPattern.MatchString(value) {
+				return fmt.Errorf("%s: invalid color %q (use #rrggbb)", color.key, value)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"lazytime/config"
+	"lazytime/storage"
+)
+
+func TestReadThemeFileValidatesColors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		path := filepath.Join(dir, "theme.json")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	theme, err := readThemeFile(write(`{"base": "light", "accent": "#8700AF"}`), dir)
+	if err != nil {
+		t.Fatalf("Expected a valid theme, got %v", err)
+	}
+	if theme.Accent != "#8700AF" || theme.Text != lightTheme.Text {
+		t.Errorf("Expected the accent over the light theme, got %+v", theme)
+	}
+
+	for _, content := range []string{
+		`{"accent": "#87af"}`,
+		`{"text": "white"}`,
+		`{"tag_colors": ["#005f87", "#00xx00"]}`,
+	} {
+		if _, err := readThemeFile(write(content), dir); err == nil || !strings.Contains(err.Error(), "invalid color") {
+			t.Errorf("Expected an invalid color error for %s, got %v", content, err)
+		}
+	}
+}
+
+func TestLoadConfigAppliesThemeDespiteOtherErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	content := `{"rounding": "sideways:15m", "working_hours": "17:00-09:00", "theme": "light"}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(config.ConfigEnvVar, path)
+	defer applyTheme(darkTheme)
+
+	var m Model
+	m.loadConfig()
+	if tagColorPalette[0] != lightTheme.TagColors[0] {
+		t.Error("Expected the light theme to be applied")
+	}
+	if m.workingHours != storage.DefaultWorkingHours {
+		t.Errorf("Expected default working hours, got %+v", m.workingHours)
+	}
+	if !m.messageError || !strings.Contains(m.message, "rounding") || !strings.Contains(m.message, "working hours") {
+		t.Errorf("Expected both errors to be reported, got %q", m.message)
+	}
+}
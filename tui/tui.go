@@ -10,7 +10,7 @@ import (
 // LaunchTUI initializes and launches the terminal UI using Bubbletea.
 func LaunchTUI() error {
 	m := NewModel()
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	_, err := p.Run()
 	return err
 }
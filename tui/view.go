@@ -2,6 +2,7 @@ package tui
 
 import (
 	"lazytime/storage"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -9,486 +10,680 @@ import (
 	"lazytime/tui/components"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
-// renderMainView renders the main application view.
-func renderMainView(m Model) string {
-	width := m.width
-	height := m.height
-	if width < 80 {
-		width = 80
-	}
-	if height < 24 {
-		height = 24
-	}
+// Layout breakpoints and the smallest window the main view is drawn for.
+const (
+	compactWidth  = 80  // Narrower windows show a single column
+	wideWidth     = 160 // Wider windows add the timeline and stats column
+	compactHeight = 24  // Shorter windows show the hero without a box
+	minWidth      = 40  // Narrower windows hide the hero and tabs
+	minHeight     = 16  // Shorter windows hide the hero and tabs
+	tinyWidth     = 20  // Smaller windows are drawn at this size
+	tinyHeight    = 6
+)
 
-	footerHeight := 2
-	contentHeight := height - footerHeight
+// Sidebar width next to the main pane in the normal layout: a share of the
+// width, within the room the goals and the month heatmap need.
+const (
+	sidebarShare    = 35 // Percent of the width
+	sidebarMinWidth = 30
+	sidebarMaxWidth = 48
+)
 
-	// Hero section (full width at top) - make it bigger
-	heroHeight := 10
-	heroSection := components.RenderHero(m.entries, m.now, width-2,
-		BorderIdle, BorderRunning, StyleIdle, HeroTimerStyle, HeroTaskStyle, HeroTagStyle,
-		GetTagColor, FormatDuration, FormatDurationShort, FormatDurationFull, clampDuration)
+// layoutMode is the column arrangement of the main view.
+type layoutMode int
 
-	// Tabs - convert ViewMode to components.ViewMode
-	var activeView components.ViewMode
-	switch m.viewMode {
-	case ViewToday:
-		activeView = components.ViewToday
-	case ViewWeek:
-		activeView = components.ViewWeek
-	}
-	tabsSection := components.RenderTabs(activeView, width, TabActive, TabInactive)
-	tabsHeight := 1      // Tabs are single line
-	verticalSpacing := 2 // Space between hero and tabs to shift content down
-
-	// Calculate available space for main content and sidebar
-	availableHeight := contentHeight - heroHeight - tabsHeight - verticalSpacing
-	if availableHeight < 10 {
-		availableHeight = 10
-	}
-
-	// Main content area (left) and sidebar (right)
-	leftWidth := int(float64(width) * 0.50)
-	rightWidth := width - leftWidth - 5
-
-	// Calculate sidebar height first
-	goalsHeight := 6    // Fixed height for goals box
-	sidebarSpacing := 1 // Space between goals and heatmap
-	// Calculate tagsHeight to use remaining space, ensuring minimum height
-	tagsHeight := availableHeight - goalsHeight - sidebarSpacing
-	if tagsHeight < 3 {
-		tagsHeight = 3
-		// Adjust goalsHeight if needed
-		if goalsHeight+sidebarSpacing+tagsHeight > availableHeight {
-			goalsHeight = availableHeight - sidebarSpacing - tagsHeight
-			if goalsHeight < 3 {
-				goalsHeight = 3
-			}
-		}
-	}
-	// Sidebar total height
-	sidebarHeight := goalsHeight + sidebarSpacing + tagsHeight
+const (
+	layoutCompact layoutMode = iota // Main pane only; the sidebar replaces it when toggled
+	layoutNormal                    // Main pane and sidebar
+	layoutWide                      // Main pane, timeline and stats, and sidebar
+)
 
-	// Set main content height to match sidebar height
-	mainHeight := sidebarHeight
-	if mainHeight < 5 {
-		mainHeight = 5
+// layoutModeFor returns the layout mode for a window width.
+func layoutModeFor(width int) layoutMode {
+	switch {
+	case width < compactWidth:
+		return layoutCompact
+	case width >= wideWidth:
+		return layoutWide
+	default:
+		return layoutNormal
 	}
+}
 
-	// Calculate time ranges based on view mode
-	var startUTC, endUTC time.Time
-	tz := m.now.Location()
-	today := m.now
+// layout holds the sizes of the main view sections for the current window.
+// Widths and heights are those of the boxes without their border; a width
+// of 0 means the column is hidden.
+type layout struct {
+	mode            layoutMode
+	width           int
+	height          int
+	minimal         bool // Hero and tabs hidden in windows below minWidth or minHeight
+	compactHero     bool // Hero drawn on one line without a box
+	heroHeight      int
+	verticalSpacing int
+	leftWidth       int // Main pane
+	middleWidth     int // Timeline and stats column of the wide layout
+	rightWidth      int // Sidebar
+	mainHeight      int
+	goalsHeight     int
+	tagsHeight      int // Month heatmap below the goals, 0 if it does not fit
+}
 
-	switch m.viewMode {
-	case ViewToday:
-		todayStart := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, tz)
-		todayEnd := todayStart.AddDate(0, 0, 1)
-		startUTC = storage.ToUTC(todayStart)
-		endUTC = storage.ToUTC(todayEnd)
-	case ViewWeek:
-		weekday := int(today.Weekday())
-		if weekday == 0 {
-			weekday = 7
+// layout computes the section sizes of the main view.
+func (m Model) layout() layout {
+	width := max(m.width, tinyWidth)
+	height := max(m.height, tinyHeight)
+	l := layout{mode: layoutModeFor(width), width: width, height: height}
+
+	// Tiny windows keep only the panes and the footer
+	l.minimal = width < minWidth || height < minHeight
+	l.compactHero = height < compactHeight
+	tabsHeight := 1
+	switch {
+	case l.minimal:
+		tabsHeight = 0
+	case l.compactHero:
+		l.heroHeight = 1
+	default:
+		l.heroHeight = BorderIdle.GetVerticalFrameSize() + 1
+		l.verticalSpacing = 2 // Space between hero and tabs to shift content down
+	}
+	footerHeight := 1
+	frame := BoxStyle.GetVerticalBorderSize()
+	frameWidth := BoxStyle.GetHorizontalBorderSize()
+
+	// Rows left for the boxes of the main pane and the sidebar
+	availableHeight := height - l.heroHeight - l.verticalSpacing - tabsHeight - footerHeight
+	l.mainHeight = max(availableHeight-frame, 1)
+
+	// Columns: boxes separated by a space
+	sidebar := m.showSidebar(l.mode)
+	switch l.mode {
+	case layoutCompact:
+		if sidebar {
+			l.rightWidth = width - frameWidth
+		} else {
+			l.leftWidth = width - frameWidth
 		}
-		weekday-- // Monday = 0
-		weekStart := today.AddDate(0, 0, -weekday)
-		weekStartLocal := time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day(), 0, 0, 0, 0, tz)
-		weekEndLocal := weekStartLocal.AddDate(0, 0, 7)
-		startUTC = storage.ToUTC(weekStartLocal)
-		endUTC = storage.ToUTC(weekEndLocal)
-	}
-
-	// Main content (tree view)
-	var mainContent string
-	if m.viewMode == ViewWeek {
-		mainContent = renderWeekView(m.entries, startUTC, endUTC, m.now, leftWidth, mainHeight, m.scrollOffset)
-	} else if m.viewMode == ViewToday {
-		mainContent = renderTodayView(m.entries, startUTC, endUTC, m.now, leftWidth, mainHeight, m.scrollOffset)
-	} else {
-		groups := GroupByTag(m.entries, startUTC, endUTC, m.now)
-		// Convert to components.TagGroup
-		compGroups := make([]components.TagGroup, len(groups))
-		for i, g := range groups {
-			compGroups[i] = components.TagGroup{
-				Tag:      g.Tag,
-				Duration: g.Duration,
-				Entries:  g.Entries,
-				Tasks:    g.Tasks,
-				TaskList: make([]components.TaskItem, len(g.TaskList)),
-			}
-			for j, t := range g.TaskList {
-				compGroups[i].TaskList[j] = components.TaskItem{
-					Text:     t.Text,
-					Duration: t.Duration,
-					Start:    t.Start,
-					End:      t.End,
-				}
-			}
+	case layoutWide:
+		if sidebar {
+			inner := width - 3*frameWidth - 2
+			l.rightWidth = inner * 28 / 100
+			l.leftWidth = (inner - l.rightWidth) / 2
+			l.middleWidth = inner - l.rightWidth - l.leftWidth
+		} else {
+			inner := width - 2*frameWidth - 1
+			l.leftWidth = inner / 2
+			l.middleWidth = inner - l.leftWidth
+		}
+	default:
+		if sidebar {
+			inner := width - 2*frameWidth - 1
+			l.rightWidth = min(max(inner*sidebarShare/100, sidebarMinWidth), sidebarMaxWidth)
+			l.leftWidth = inner - l.rightWidth
+		} else {
+			l.leftWidth = width - frameWidth
 		}
-		mainContent = components.RenderTree(compGroups, leftWidth, mainHeight, TreeTagStyle, TreeTaskStyle, TreeDurationStyle, BoxStyle, GetTagColor, FormatDurationShort)
 	}
 
-	// Sidebar: Goals and Tags (heights already calculated above)
-	goalsSection := components.RenderGoalProgress(m.entries, m.now, m.targetToday, m.targetWeek, rightWidth, clampDuration, GetProgressColor, FormatDurationShort)
-	goalsBox := BoxStyle.Width(rightWidth).Height(goalsHeight).Render(goalsSection)
+	// Sidebar: goals box, then the month heatmap in the remaining rows
+	l.goalsHeight = min(7, max(availableHeight-frame, 1))
+	l.tagsHeight = availableHeight - (l.goalsHeight + frame) - frame
+	if l.tagsHeight < components.MonthHeatmapMinHeight {
+		l.tagsHeight = 0
+	}
+	return l
+}
 
-	heatmapSection := components.RenderMonthHeatmap(m.entries, m.now, rightWidth, tagsHeight, clampDuration, BoxStyle)
+// showSidebar reports whether the sidebar is shown in the given layout
+// mode: by default in all but the compact layout, flipped by the sidebar
+// key.
+func (m Model) showSidebar(mode layoutMode) bool {
+	return (mode != layoutCompact) != m.sidebarToggled
+}
 
-	sidebar := lipgloss.JoinVertical(lipgloss.Left, goalsBox, heatmapSection)
+// renderMainView renders the main application view.
+func renderMainView(m Model) string {
+	l := m.layout()
 
-	// Combine main content and sidebar
-	contentRow := lipgloss.JoinHorizontal(lipgloss.Left, mainContent, " ", sidebar)
+	// Columns: main pane, timeline and stats, sidebar
+	var columns []string
+	if l.leftWidth > 0 {
+		columns = append(columns, renderPane(m, m.pane, l.leftWidth, l.mainHeight))
+	}
+	if l.middleWidth > 0 {
+		columns = append(columns, renderMiddleColumn(m, l))
+	}
+	if l.rightWidth > 0 {
+		sidebar := renderGoals(m, l)
+		if l.tagsHeight > 0 {
+			heatmapSection := components.RenderMonthHeatmap(m.filteredEntries(), m.now, l.rightWidth, l.tagsHeight, clampDuration, BoxStyle)
+			sidebar = lipgloss.JoinVertical(lipgloss.Left, sidebar, heatmapSection)
+		}
+		columns = append(columns, sidebar)
+	}
+	contentRow := lipgloss.JoinHorizontal(lipgloss.Top, intersperse(columns, " ")...)
 
 	// Footer
-	footer := renderFooter(width)
+	footer := renderFooter(l.width, m.message, m.messageError, m.keys.footerItems())
 
 	// Combine everything with spacing
 	var verticalElements []string
-	verticalElements = append(verticalElements, heroSection)
-	// Add vertical spacing
-	for i := 0; i < verticalSpacing; i++ {
-		verticalElements = append(verticalElements, "")
+	if !l.minimal {
+		verticalElements = append(verticalElements, renderHero(m, l))
+		// Add vertical spacing
+		for i := 0; i < l.verticalSpacing; i++ {
+			verticalElements = append(verticalElements, "")
+		}
+		verticalElements = append(verticalElements, components.RenderTabs(m.activeTab(), m.rangeLabel(), m.tagFilter, l.width, TabActive, TabInactive))
 	}
-	verticalElements = append(verticalElements, tabsSection, contentRow, footer)
+	verticalElements = append(verticalElements, contentRow, footer)
 	return lipgloss.JoinVertical(lipgloss.Left, verticalElements...)
 }
 
-// renderModalView renders the modal overlay.
-func renderModalView(m Model) string {
-	width := m.width
-	height := m.height
-	if width < 80 {
-		width = 80
-	}
-	if height < 24 {
-		height = 24
-	}
-
-	// Get tag suggestions if needed
-	var suggestions []string
-	if m.modalType == "new" {
-		// Extract current tag input from the single field
-		tagInput := extractCurrentTagInput(m.modalInput)
-		if tagInput != "" {
-			allTags := GetUniqueTags(m.entries)
-			suggestions = components.GetFuzzySuggestions(tagInput, allTags, 5)
-			m.modalSuggestions = suggestions
-		} else {
-			suggestions = m.modalSuggestions
+// renderPane renders pane (entry list, tag tree, timeline, gaps, stats or
+// heatmap) for the active view in a box of the given size. Cursors and
+// scroll offsets apply only to the pane shown as the main pane.
+func renderPane(m Model, pane Pane, width, height int) string {
+	startUTC, endUTC := m.viewRange()
+	switch pane {
+	case PaneTree:
+		return components.RenderTree(m.tagTree(startUTC, endUTC), width, height, m.collapsedTags, m.treeCursor,
+			TreeTagStyle, TreeTaskStyle, TreeDurationStyle, SelectedStyle, BoxStyle, GetTagColor, FormatDurationShort)
+	case PaneTimeline:
+		offset := 0
+		if pane == m.pane {
+			offset = m.timelineOffset
 		}
+		return components.RenderTimeline(m.filteredEntries(), startUTC, endUTC, m.now, width, height, offset,
+			BoxStyle, TreeDurationStyle, TimelineGapStyle, TimelineIdleStyle, ErrorStyle, GetTagColor, FormatDurationShort)
+	case PaneGaps:
+		return components.RenderGapList(m.gaps(), m.now.Location(), width, height, m.gapCursor,
+			TreeDurationStyle, TimelineGapStyle, SelectedStyle, BoxStyle, FormatDurationShort)
+	case PaneStats:
+		return renderStats(m, width, height)
+	case PaneHeatmap:
+		yearStart := components.YearHeatmapStart(m.now, width)
+		totals := DailyTotals(m.filteredEntries(), storage.ToUTC(yearStart), m.now, m.now)
+		return components.RenderYearHeatmap(totals, m.now, m.heatmapCursor, m.targetToday, width, height,
+			BoxStyle, TreeDurationStyle, CursorStyle, FormatDurationShort)
+	default:
+		return renderEntryList(m.entries, m.listRows(), m.now, width, height, m.scrollOffset, m.selected, m.emptyListText())
 	}
+}
 
-	// Render main view first (dimmed)
-	mainView := renderMainView(m)
-	dimmed := lipgloss.NewStyle().Foreground(lipgloss.Color("#444444")).Render(mainView)
-
-	// Render modal on top
-	modal := components.RenderModal(m.modalType, m.modalInput, suggestions, m.modalSelected, width, height, BoxStyle, TabActive, TabInactive, FooterStyle)
-
-	// Combine (modal should overlay)
-	return lipgloss.JoinVertical(lipgloss.Left, dimmed, modal)
+// renderMiddleColumn renders the wide layout's middle column: the timeline
+// above the stats, leaving out the one shown in the main pane.
+func renderMiddleColumn(m Model, l layout) string {
+	switch m.pane {
+	case PaneTimeline:
+		return renderPane(m, PaneStats, l.middleWidth, l.mainHeight)
+	case PaneStats:
+		return renderPane(m, PaneTimeline, l.middleWidth, l.mainHeight)
+	}
+	frame := BoxStyle.GetVerticalBorderSize()
+	timelineHeight := (l.mainHeight - frame) / 2
+	statsHeight := l.mainHeight - frame - timelineHeight
+	return lipgloss.JoinVertical(lipgloss.Left,
+		renderPane(m, PaneTimeline, l.middleWidth, timelineHeight),
+		renderPane(m, PaneStats, l.middleWidth, statsHeight))
 }
 
-// renderTodayView renders a flat list of today's tasks sorted by completion time (most recent first).
-func renderTodayView(entries []storage.Entry, startUTC, endUTC, now time.Time, width, height, scrollOffset int) string {
-	// Filter entries for today
-	var todayEntries []storage.Entry
-	for _, entry := range entries {
-		if clampDuration(entry, startUTC, endUTC, now) > 0 {
-			todayEntries = append(todayEntries, entry)
+// intersperse returns items with sep between each pair.
+func intersperse(items []string, sep string) []string {
+	var result []string
+	for i, item := range items {
+		if i > 0 {
+			result = append(result, sep)
 		}
+		result = append(result, item)
 	}
+	return result
+}
 
-	if len(todayEntries) == 0 {
-		return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("No entries today."))
+// renderHero renders the hero section with the running entry, on one line
+// without a box in short windows.
+func renderHero(m Model, l layout) string {
+	borderIdle, borderRunning := BorderIdle, BorderRunning
+	if l.compactHero {
+		borderIdle = lipgloss.NewStyle().Padding(0, 1)
+		borderRunning = borderIdle
 	}
+	return components.RenderHero(m.entries, m.now, l.width-2,
+		borderIdle, borderRunning, StyleIdle, HeroTimerStyle, HeroTaskStyle, HeroTagStyle,
+		GetTagColor, FormatDuration, FormatDurationShort, FormatDurationFull, clampDuration)
+}
 
-	// Sort by end time (descending - most recent first)
-	// For open entries, use 'now' as the end time for sorting
-	sort.Slice(todayEntries, func(i, j int) bool {
-		endI := now
-		if todayEntries[i].End != nil {
-			endI = *todayEntries[i].End
-		}
-		endJ := now
-		if todayEntries[j].End != nil {
-			endJ = *todayEntries[j].End
-		}
-		return endI.After(endJ)
-	})
-
-	// Convert UTC times to local timezone for display
-	tz := now.Location()
-
-	// Build all lines first (without height limit)
-	var allLines []string
+// renderGoals renders the goals box at the top of the sidebar, cutting off
+// the goals that do not fit in short windows.
+func renderGoals(m Model, l layout) string {
+	goalsSection := components.RenderGoalProgress(m.filteredEntries(), m.now, m.targetToday, m.targetWeek, l.rightWidth, clampDuration, GetProgressColor, FormatDurationShort)
+	lines := strings.Split(goalsSection, "\n")
+	if visible := max(l.goalsHeight-BoxStyle.GetVerticalPadding(), 1); len(lines) > visible {
+		goalsSection = strings.Join(lines[:visible], "\n")
+	}
+	return BoxStyle.Width(l.rightWidth).Height(l.goalsHeight).Render(goalsSection)
+}
 
-	for _, entry := range todayEntries {
-		// Check if this is an active task (currently being worked on)
-		isActive := entry.End == nil
+// activeTab converts the view mode to the tab shown as active.
+func (m Model) activeTab() components.ViewMode {
+	switch m.viewMode {
+	case ViewWeek:
+		return components.ViewWeek
+	case ViewMonth:
+		return components.ViewMonth
+	case ViewYear:
+		return components.ViewYear
+	default:
+		return components.ViewToday
+	}
+}
 
-		// Convert start/end times to local timezone
-		startLocal := entry.Start.In(tz)
-		endLocal := now
-		if entry.End != nil {
-			endLocal = entry.End.In(tz)
-		}
+// viewRange returns the UTC time range covered by the active view.
+func (m Model) viewRange() (time.Time, time.Time) {
+	tz := m.now.Location()
+	today := m.now
+	todayStart := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, tz)
 
-		// Format time range - show "DNF" for active tasks
-		var timeRange string
-		if isActive {
-			timeRange = startLocal.Format("15:04") + " - DNF"
-		} else {
-			timeRange = startLocal.Format("15:04") + " - " + endLocal.Format("15:04")
+	switch m.viewMode {
+	case ViewWeek:
+		weekday := int(today.Weekday())
+		if weekday == 0 {
+			weekday = 7
 		}
+		weekday-- // Monday = 0
+		weekStartLocal := todayStart.AddDate(0, 0, -weekday+7*m.rangeOffset)
+		weekEndLocal := weekStartLocal.AddDate(0, 0, 7)
+		return storage.ToUTC(weekStartLocal), storage.ToUTC(weekEndLocal)
+	case ViewMonth:
+		monthStartLocal := time.Date(today.Year(), today.Month()+time.Month(m.rangeOffset), 1, 0, 0, 0, 0, tz)
+		monthEndLocal := monthStartLocal.AddDate(0, 1, 0)
+		return storage.ToUTC(monthStartLocal), storage.ToUTC(monthEndLocal)
+	case ViewYear:
+		yearStartLocal := time.Date(today.Year()+m.rangeOffset, time.January, 1, 0, 0, 0, 0, tz)
+		yearEndLocal := yearStartLocal.AddDate(1, 0, 0)
+		return storage.ToUTC(yearStartLocal), storage.ToUTC(yearEndLocal)
+	default:
+		dayStart := todayStart.AddDate(0, 0, m.rangeOffset)
+		dayEnd := dayStart.AddDate(0, 0, 1)
+		return storage.ToUTC(dayStart), storage.ToUTC(dayEnd)
+	}
+}
 
-		// Extract task text without tags
-		taskText := removeTags(entry.Text)
-
-		// Extract tags
-		tags := entry.Tags()
-
-		// Build the line: "- (HH:MM - HH:MM) <task> <tag1> <tag2>"
-		prefix := "- (" + timeRange + ") " + taskText
+// rangeLabel describes the range shown by the active view.
+func (m Model) rangeLabel() string {
+	startUTC, endUTC := m.viewRange()
+	tz := m.now.Location()
+	start := startUTC.In(tz)
+	switch m.viewMode {
+	case ViewWeek:
+		last := endUTC.In(tz).AddDate(0, 0, -1)
+		return start.Format("2006-01-02") + " - " + last.Format("2006-01-02")
+	case ViewMonth:
+		return start.Format("January 2006")
+	case ViewYear:
+		return start.Format("2006")
+	default:
+		return start.Format("Mon 2006-01-02")
+	}
+}
 
-		// Render tags with colors
-		var tagParts []string
-		for _, tag := range tags {
-			tagColor := GetTagColor(tag)
-			tagStyle := lipgloss.NewStyle().Foreground(tagColor)
-			tagParts = append(tagParts, tagStyle.Render("#"+tag))
-		}
-		tagsStr := strings.Join(tagParts, " ")
-
-		// Calculate available width for the line
-		// Account for box padding (2 chars on each side = 4 total)
-		availableWidth := width - 4
-
-		// Get visible widths (accounting for ANSI escape codes)
-		prefixVisible := lipgloss.Width(prefix)
-		tagsVisible := lipgloss.Width(tagsStr)
-
-		var line string
-		if len(tags) > 0 {
-			if prefixVisible+tagsVisible+1 <= availableWidth {
-				// Tags fit on the same line - align to right
-				spacesNeeded := availableWidth - prefixVisible - tagsVisible
-				line = prefix + strings.Repeat(" ", spacesNeeded) + tagsStr
-			} else {
-				// Tags don't fit - put them after task text with a space
-				line = prefix + " " + tagsStr
+// tagTree groups the entries in the given range into a tag hierarchy
+// for components.RenderTree.
+func (m Model) tagTree(startUTC, endUTC time.Time) []components.TagGroup {
+	groups := GroupByTag(m.filteredEntries(), startUTC, endUTC, m.now, m.rounding)
+	if m.tagFilter != "" {
+		// Only show the filtered tag's subtree
+		var within []TagGroup
+		for _, g := range groups {
+			if storage.IsTagWithin(g.Tag, m.tagFilter) {
+				within = append(within, g)
 			}
-		} else {
-			// No tags
-			line = prefix
 		}
-
-		// Truncate if line exceeds available width
-		if lipgloss.Width(line) > availableWidth {
-			// Use lipgloss to truncate while preserving ANSI codes
-			line = lipgloss.Place(availableWidth, 1, lipgloss.Left, lipgloss.Top, line)
+		groups = within
+	}
+	// Convert to components.TagGroup
+	compGroups := make([]components.TagGroup, len(groups))
+	for i, g := range groups {
+		compGroups[i] = components.TagGroup{
+			Tag:         g.Tag,
+			Name:        g.Name,
+			Depth:       g.Depth,
+			HasChildren: g.HasChildren,
+			Duration:    g.Duration,
+			Entries:     g.Entries,
+			Tasks:       g.Tasks,
+			TaskList:    make([]components.TaskItem, len(g.TaskList)),
 		}
-
-		// Apply green styling to active tasks
-		if isActive {
-			line = StyleRunning.Render(line)
+		for j, t := range g.TaskList {
+			compGroups[i].TaskList[j] = components.TaskItem{
+				Text:     t.Text,
+				Duration: t.Duration,
+				Start:    t.Start,
+				End:      t.End,
+			}
 		}
-
-		allLines = append(allLines, line)
 	}
+	return compGroups
+}
 
-	// Calculate visible lines and apply scroll offset
-	visibleLines := height - 2
-	maxScrollOffset := 0
-	if len(allLines) > visibleLines {
-		maxScrollOffset = len(allLines) - visibleLines
+// renderModalView renders the modal overlay.
+func renderModalView(m Model) string {
+	l := m.layout()
+	width, height := l.width, l.height
+
+	// Suggestions are refreshed as the user types
+	suggestions := m.modalSuggestions
+	if m.suggestionKind == suggestTags {
+		suggestions = make([]string, len(m.modalSuggestions))
+		for i, tag := range m.modalSuggestions {
+			suggestions[i] = "#" + tag
+		}
 	}
 
-	// Clamp scroll offset
-	if scrollOffset > maxScrollOffset {
-		scrollOffset = maxScrollOffset
+	// Render main view first, dimmed without its own colors
+	dimmed := DimmedStyle.Render(ansi.Strip(renderMainView(m)))
+
+	// The focused input shows the cursor
+	input := components.RenderInput(m.modalInput, m.modalCursor, CursorStyle)
+	if m.modalType == "filter" {
+		// The modal draws the leading # itself
+		if tag, ok := strings.CutPrefix(m.modalInput, "#"); ok {
+			input = components.RenderInput(tag, m.modalCursor-1, CursorStyle)
+		}
 	}
-	if scrollOffset < 0 {
-		scrollOffset = 0
+	fields := slices.Clone(m.modalFields)
+	if m.modalType == "edit" {
+		fields[m.modalField] = components.RenderInput(fields[m.modalField], m.modalCursor, CursorStyle)
 	}
 
-	// Apply scroll offset and limit to visible lines
-	startIdx := scrollOffset
-	endIdx := scrollOffset + visibleLines
-	if endIdx > len(allLines) {
-		endIdx = len(allLines)
+	// Render modal on top
+	hint := m.keys.modalHint(m.modalType, len(suggestions) > 0)
+	var modal string
+	if m.modalType == "help" {
+		modal = components.RenderHelpModal(m.helpLines(), m.helpOffset, m.keys.helpHint(), width, height, BoxStyle, FooterStyle)
+	} else if m.modalType == "search" {
+		modal = components.RenderSearchModal(m.modalInput, input, m.searchResults(), m.modalSelected, m.now.Location(), hint, width, height, BoxStyle, SelectedStyle, TreeDurationStyle, FooterStyle, FormatDurationShort)
+	} else if m.modalType == "edit" {
+		modal = components.RenderEditModal(fields, m.modalField, m.modalError, hint, suggestions, m.modalSelected, width, height, BoxStyle, TabActive, TabInactive, FooterStyle, ErrorStyle)
+	} else {
+		modal = components.RenderModal(m.modalType, m.modalPrompt, input, m.modalError, hint, suggestions, m.modalSelected, width, height, BoxStyle, TabActive, TabInactive, FooterStyle, ErrorStyle)
 	}
 
-	var lines []string
-	if startIdx < len(allLines) {
-		lines = allLines[startIdx:endIdx]
-	}
+	return components.Overlay(dimmed, modal, width, height)
+}
 
-	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
-	return BoxStyle.Width(width).Height(height).Render(content)
+// listRow is one line of the entry list: either a day header or an entry.
+type listRow struct {
+	header     string // Day header text (headers only)
+	entryIndex int    // Index into Model.entries, -1 for headers
+}
+
+// sortByEndDesc sorts entry indices by end time (descending - most recent first).
+// For open entries, 'now' is used as the end time.
+func sortByEndDesc(entries []storage.Entry, indices []int, now time.Time) {
+	sort.SliceStable(indices, func(i, j int) bool {
+		endI := now
+		if entries[indices[i]].End != nil {
+			endI = *entries[indices[i]].End
+		}
+		endJ := now
+		if entries[indices[j]].End != nil {
+			endJ = *entries[indices[j]].End
+		}
+		return endI.After(endJ)
+	})
 }
 
-// renderWeekView renders a list of the week's tasks grouped by day of the week.
-func renderWeekView(entries []storage.Entry, startUTC, endUTC, now time.Time, width, height, scrollOffset int) string {
-	// Filter entries for the week
-	var weekEntries []storage.Entry
-	for _, entry := range entries {
+// buildTodayRows returns the rows of the Today list: entries overlapping
+// the range, sorted by completion time (most recent first).
+func buildTodayRows(entries []storage.Entry, startUTC, endUTC, now time.Time) []listRow {
+	var indices []int
+	for i, entry := range entries {
 		if clampDuration(entry, startUTC, endUTC, now) > 0 {
-			weekEntries = append(weekEntries, entry)
+			indices = append(indices, i)
 		}
 	}
+	sortByEndDesc(entries, indices, now)
 
-	if len(weekEntries) == 0 {
-		return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("No entries this week."))
+	rows := make([]listRow, len(indices))
+	for i, idx := range indices {
+		rows[i] = listRow{entryIndex: idx}
 	}
+	return rows
+}
 
+// buildGroupedRows returns the rows of a grouped list: entries under a
+// header per period (day or month), most recent period first. group maps an
+// entry's local start time to the start of its period and the header text.
+func buildGroupedRows(entries []storage.Entry, startUTC, endUTC, now time.Time, group func(time.Time) (time.Time, string)) []listRow {
 	// Convert UTC times to local timezone for grouping
 	tz := now.Location()
 
-	// Group entries by day of the week
-	// Map: day index (0=Monday, 6=Sunday) -> entries for that day
-	dayGroups := make(map[int][]storage.Entry)
-
-	for _, entry := range weekEntries {
-		// Determine which day this entry belongs to (use start time)
-		startLocal := entry.Start.In(tz)
-		dayIndex := int(startLocal.Weekday())
-		if dayIndex == 0 {
-			dayIndex = 7 // Sunday = 7, but we want it to be index 6
+	groups := make(map[time.Time][]int)
+	headers := make(map[time.Time]string)
+	var keys []time.Time
+	for i, entry := range entries {
+		if clampDuration(entry, startUTC, endUTC, now) <= 0 {
+			continue
 		}
-		dayIndex-- // Monday = 0, Sunday = 6
-
-		dayGroups[dayIndex] = append(dayGroups[dayIndex], entry)
+		// Determine which period this entry belongs to (use start time)
+		key, header := group(entry.Start.In(tz))
+		if _, exists := groups[key]; !exists {
+			keys = append(keys, key)
+			headers[key] = header
+		}
+		groups[key] = append(groups[key], i)
 	}
 
-	// Day names in order (Monday to Sunday)
-	dayNames := []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+	// Descending period order, most recent first
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].After(keys[j])
+	})
 
-	// Sort entries within each day by end time (descending - most recent first)
-	for dayIndex := range dayGroups {
-		sort.Slice(dayGroups[dayIndex], func(i, j int) bool {
-			endI := now
-			if dayGroups[dayIndex][i].End != nil {
-				endI = *dayGroups[dayIndex][i].End
-			}
-			endJ := now
-			if dayGroups[dayIndex][j].End != nil {
-				endJ = *dayGroups[dayIndex][j].End
-			}
-			return endI.After(endJ)
-		})
+	var rows []listRow
+	for _, key := range keys {
+		// Sort entries within the period by end time (most recent first)
+		sortByEndDesc(entries, groups[key], now)
+
+		rows = append(rows, listRow{header: headers[key], entryIndex: -1})
+		for _, idx := range groups[key] {
+			rows = append(rows, listRow{entryIndex: idx})
+		}
 	}
+	return rows
+}
 
-	// Build all lines first (without height limit)
-	var allLines []string
+// groupByWeekday groups entries by day, headed by the weekday name.
+func groupByWeekday(t time.Time) (time.Time, string) {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return day, strings.ToLower(t.Weekday().String())
+}
 
-	// Determine today's day index (0=Monday, 6=Sunday)
-	todayLocal := now.In(tz)
-	todayDayIndex := int(todayLocal.Weekday())
-	if todayDayIndex == 0 {
-		todayDayIndex = 7 // Sunday = 7
-	}
-	todayDayIndex-- // Monday = 0, Sunday = 6
+// groupByDate groups entries by day, headed by the weekday and date.
+func groupByDate(t time.Time) (time.Time, string) {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return day, strings.ToLower(t.Format("Monday Jan 2"))
+}
 
-	// Create descending day order starting from today going backwards
-	// If today is Friday (4), order is: 4, 3, 2, 1, 0, 6, 5
-	var dayOrder []int
-	for i := 0; i < 7; i++ {
-		dayIndex := (todayDayIndex - i + 7) % 7
-		dayOrder = append(dayOrder, dayIndex)
-	}
+// groupByMonth groups entries by month, headed by the month name.
+func groupByMonth(t time.Time) (time.Time, string) {
+	month := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return month, strings.ToLower(t.Format("January"))
+}
 
-	for _, dayIndex := range dayOrder {
-		dayEntries, hasEntries := dayGroups[dayIndex]
-		if !hasEntries || len(dayEntries) == 0 {
-			continue // Skip days with no entries
+// listRows returns the rows of the entry list for the active view,
+// restricted to entries passing the tag filter.
+func (m Model) listRows() []listRow {
+	startUTC, endUTC := m.viewRange()
+	var rows []listRow
+	switch m.viewMode {
+	case ViewWeek:
+		rows = buildGroupedRows(m.entries, startUTC, endUTC, m.now, groupByWeekday)
+	case ViewMonth:
+		rows = buildGroupedRows(m.entries, startUTC, endUTC, m.now, groupByDate)
+	case ViewYear:
+		rows = buildGroupedRows(m.entries, startUTC, endUTC, m.now, groupByMonth)
+	default:
+		rows = buildTodayRows(m.entries, startUTC, endUTC, m.now)
+	}
+	if m.tagFilter == "" {
+		return rows
+	}
+
+	// Drop filtered entries, and headers left without entries
+	var filtered []listRow
+	var header *listRow
+	for i, row := range rows {
+		if row.entryIndex < 0 {
+			header = &rows[i]
+			continue
 		}
+		if !m.matchesFilter(m.entries[row.entryIndex]) {
+			continue
+		}
+		if header != nil {
+			filtered = append(filtered, *header)
+			header = nil
+		}
+		filtered = append(filtered, row)
+	}
+	return filtered
+}
 
-		// Day header: "> monday" (styled like tree headers)
-		dayHeader := "> " + TreeTagStyle.Render(dayNames[dayIndex])
-		allLines = append(allLines, dayHeader)
+// emptyListText is shown when the active view's range has no entries.
+func (m Model) emptyListText() string {
+	if m.rangeOffset != 0 {
+		if m.viewMode == ViewToday {
+			return "No entries on " + m.rangeLabel() + "."
+		}
+		return "No entries in " + m.rangeLabel() + "."
+	}
+	switch m.viewMode {
+	case ViewWeek:
+		return "No entries this week."
+	case ViewMonth:
+		return "No entries this month."
+	case ViewYear:
+		return "No entries this year."
+	default:
+		return "No entries today."
+	}
+}
 
-		// Add tasks for this day
-		for _, entry := range dayEntries {
-			// Check if this is an active task (currently being worked on)
-			isActive := entry.End == nil
+// renderEntryLine renders an entry as "- (HH:MM - HH:MM) <task> <tag1> <tag2>",
+// with tags right-aligned when they fit. The selected entry is highlighted.
+func renderEntryLine(entry storage.Entry, now time.Time, width int, selected bool) string {
+	// Convert UTC times to local timezone for display
+	tz := now.Location()
 
-			// Convert start/end times to local timezone
-			startLocal := entry.Start.In(tz)
-			endLocal := now
-			if entry.End != nil {
-				endLocal = entry.End.In(tz)
-			}
+	// Check if this is an active task (currently being worked on)
+	isActive := entry.End == nil
 
-			// Format time range - show "DNF" for active tasks
-			var timeRange string
-			if isActive {
-				timeRange = startLocal.Format("15:04") + " - DNF"
-			} else {
-				timeRange = startLocal.Format("15:04") + " - " + endLocal.Format("15:04")
-			}
+	// Convert start/end times to local timezone
+	startLocal := entry.Start.In(tz)
+	endLocal := now
+	if entry.End != nil {
+		endLocal = entry.End.In(tz)
+	}
 
-			// Extract task text without tags
-			taskText := removeTags(entry.Text)
+	// Format time range - show "DNF" for active tasks
+	var timeRange string
+	if isActive {
+		timeRange = startLocal.Format("15:04") + " - DNF"
+	} else {
+		timeRange = startLocal.Format("15:04") + " - " + endLocal.Format("15:04")
+	}
 
-			// Extract tags
-			tags := entry.Tags()
+	// Extract task text without tags
+	taskText := removeTags(entry.Text)
 
-			// Build the line: "- (HH:MM - HH:MM) <task> <tag1> <tag2>"
-			prefix := "- (" + timeRange + ") " + taskText
+	// Extract tags
+	tags := entry.Tags()
 
-			// Render tags with colors
-			var tagParts []string
-			for _, tag := range tags {
-				tagColor := GetTagColor(tag)
-				tagStyle := lipgloss.NewStyle().Foreground(tagColor)
-				tagParts = append(tagParts, tagStyle.Render("#"+tag))
-			}
-			tagsStr := strings.Join(tagParts, " ")
-
-			// Calculate available width for the line
-			// Account for box padding (2 chars on each side = 4 total)
-			availableWidth := width - 4
-
-			// Get visible widths (accounting for ANSI escape codes)
-			prefixVisible := lipgloss.Width(prefix)
-			tagsVisible := lipgloss.Width(tagsStr)
-
-			var line string
-			if len(tags) > 0 {
-				if prefixVisible+tagsVisible+1 <= availableWidth {
-					// Tags fit on the same line - align to right
-					spacesNeeded := availableWidth - prefixVisible - tagsVisible
-					line = prefix + strings.Repeat(" ", spacesNeeded) + tagsStr
-				} else {
-					// Tags don't fit - put them after task text with a space
-					line = prefix + " " + tagsStr
-				}
-			} else {
-				// No tags
-				line = prefix
-			}
+	// Build the line: "- (HH:MM - HH:MM) <task> <tag1> <tag2>"
+	marker := "- "
+	if selected {
+		marker = "> "
+	}
+	prefix := marker + "(" + timeRange + ") " + taskText
 
-			// Truncate if line exceeds available width
-			if lipgloss.Width(line) > availableWidth {
-				// Use lipgloss to truncate while preserving ANSI codes
-				line = lipgloss.Place(availableWidth, 1, lipgloss.Left, lipgloss.Top, line)
-			}
+	// Render tags with colors (plain when selected so the highlight is not interrupted)
+	var tagParts []string
+	for _, tag := range tags {
+		if selected {
+			tagParts = append(tagParts, "#"+tag)
+			continue
+		}
+		tagColor := GetTagColor(tag)
+		tagStyle := lipgloss.NewStyle().Foreground(tagColor)
+		tagParts = append(tagParts, tagStyle.Render("#"+tag))
+	}
+	tagsStr := strings.Join(tagParts, " ")
+
+	// Calculate available width for the line
+	// Account for box padding (2 chars on each side = 4 total)
+	availableWidth := width - 4
+
+	// Get visible widths (accounting for ANSI escape codes)
+	prefixVisible := lipgloss.Width(prefix)
+	tagsVisible := lipgloss.Width(tagsStr)
+
+	var line string
+	if len(tags) > 0 {
+		if prefixVisible+tagsVisible+1 <= availableWidth {
+			// Tags fit on the same line - align to right
+			spacesNeeded := availableWidth - prefixVisible - tagsVisible
+			line = prefix + strings.Repeat(" ", spacesNeeded) + tagsStr
+		} else {
+			// Tags don't fit - put them after task text with a space
+			line = prefix + " " + tagsStr
+		}
+	} else {
+		// No tags
+		line = prefix
+	}
 
-			// Apply green styling to active tasks
-			if isActive {
-				line = StyleRunning.Render(line)
-			}
+	// Truncate if line exceeds available width
+	if lipgloss.Width(line) > availableWidth {
+		// Truncate while preserving ANSI codes
+		line = ansi.Truncate(line, availableWidth, "…")
+	}
 
-			allLines = append(allLines, line)
+	if selected {
+		style := SelectedStyle
+		if isActive {
+			style = style.Copy().Foreground(StyleRunning.GetForeground())
 		}
+		return style.Width(availableWidth).Render(line)
 	}
 
-	if len(allLines) == 0 {
-		return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, lipgloss.NewStyle().Foreground(lipgloss.Color("#888888")).Render("No entries this week."))
+	// Apply green styling to active tasks
+	if isActive {
+		line = StyleRunning.Render(line)
+	}
+	return line
+}
+
+// renderEntryList renders list rows in a box, applying the scroll offset.
+// The entry at index selected (into entries) is highlighted; pass -1 for none.
+func renderEntryList(entries []storage.Entry, rows []listRow, now time.Time, width, height, scrollOffset, selected int, emptyText string) string {
+	if len(rows) == 0 {
+		return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, MutedStyle.Render(emptyText))
+	}
+
+	// Build all lines first (without height limit)
+	var allLines []string
+	for _, row := range rows {
+		if row.entryIndex < 0 {
+			// Day header: "> monday" (styled like tree headers)
+			allLines = append(allLines, "> "+TreeTagStyle.Render(row.header))
+			continue
+		}
+		allLines = append(allLines, renderEntryLine(entries[row.entryIndex], now, width, row.entryIndex == selected))
 	}
 
 	// Calculate visible lines and apply scroll offset
@@ -522,8 +717,30 @@ func renderWeekView(entries []storage.Entry, startUTC, endUTC, now time.Time, wi
 	return BoxStyle.Width(width).Height(height).Render(content)
 }
 
-// renderFooter renders the footer with help text.
-func renderFooter(width int) string {
-	helpLine := "[1/2] Views  [n] New  [x] Stop  [r] Reload  [e/?] Help  [q] Quit"
-	return FooterStyle.Width(width).Render(helpLine)
+// renderFooter renders the footer with the key hints in items, or the
+// status message if one is set. Hints before the last two (help and quit)
+// are dropped from the end until the footer fits.
+func renderFooter(width int, message string, isError bool, items []components.HelpItem) string {
+	if message != "" {
+		style := SuccessStyle
+		if isError {
+			style = ErrorStyle
+		}
+		return style.Width(width).Render(ansi.Truncate(message, width, "…"))
+	}
+	hints := make([]string, len(items))
+	for i, item := range items {
+		hints[i] = "[" + item.Keys + "] " + item.Desc
+	}
+	helpLine := strings.Join(hints, "  ")
+	for lipgloss.Width(helpLine) > width && len(hints) > 2 {
+		hints = slices.Delete(hints, len(hints)-3, len(hints)-2)
+		helpLine = strings.Join(hints, "  ")
+	}
+	return FooterStyle.Width(width).Render(ansi.Truncate(helpLine, width, "…"))
+}
+
+// helpLines lays out the help modal's content for the active key bindings.
+func (m Model) helpLines() []string {
+	return components.HelpLines(m.keys.helpSections(), HeroTaskStyle.Bold(true), HelpKeyStyle)
 }
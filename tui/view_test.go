@@ -0,0 +1,155 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"lazytime/tui/components"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// resize sends a window size message to m.
+func resize(m Model, width, height int) Model {
+	next, _ := m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	return next.(Model)
+}
+
+// checkFits fails the test if view is larger than width x height.
+func checkFits(t *testing.T, view string, width, height int) {
+	t.Helper()
+	lines := strings.Split(view, "\n")
+	if len(lines) > height {
+		t.Errorf("Expected at most %d lines, got %d:\n%s", height, len(lines), ansi.Strip(view))
+	}
+	for i, line := range lines {
+		if w := ansi.StringWidth(line); w > width {
+			t.Errorf("Line %d is %d cells wide, more than %d: %q", i, w, width, ansi.Strip(line))
+		}
+	}
+}
+
+func TestLayoutBreakpoints(t *testing.T) {
+	tests := []struct {
+		name        string
+		width       int
+		height      int
+		wantMinimal bool
+		wantHero    int // Rows of the hero
+		wantColumns int
+	}{
+		{"tiny", 30, 12, true, 0, 1},
+		{"short", 100, 14, true, 0, 2},
+		{"compact", 60, 30, false, 5, 1},
+		{"low", 100, 20, false, 1, 2},
+		{"normal", 120, 40, false, 5, 2},
+		{"wide", 200, 50, false, 5, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := resize(mouseModel(t), tt.width, tt.height)
+			l := m.layout()
+			columns := 0
+			for _, width := range []int{l.leftWidth, l.middleWidth, l.rightWidth} {
+				if width > 0 {
+					columns++
+				}
+			}
+			if l.minimal != tt.wantMinimal || l.heroHeight != tt.wantHero || columns != tt.wantColumns {
+				t.Errorf("Expected minimal=%v hero=%d columns=%d, got minimal=%v hero=%d columns=%d",
+					tt.wantMinimal, tt.wantHero, tt.wantColumns, l.minimal, l.heroHeight, columns)
+			}
+
+			view := m.View()
+			checkFits(t, view, tt.width, tt.height)
+			tabs := strings.Contains(ansi.Strip(view), "Today") && strings.Contains(ansi.Strip(view), "Month")
+			if tabs == tt.wantMinimal {
+				t.Errorf("Expected tabs shown %v, got %v", !tt.wantMinimal, tabs)
+			}
+		})
+	}
+}
+
+func TestTinyWindowHidesHeroAndTabs(t *testing.T) {
+	m := resize(mouseModel(t), 30, 10)
+	if regions := m.regions(); regions.tabs != (rect{}) || regions.main.y != 0 {
+		t.Errorf("Expected no tabs and the main pane at the top, got %+v", regions)
+	}
+	x, y := screenPos(t, m, "(16:40 - 16:50)", -1)
+	if m = mouse(m, x, y, tea.MouseButtonLeft); m.selected != 46 {
+		t.Errorf("Expected clicks to hit the entry list, got selected %d", m.selected)
+	}
+
+	// Windows below the smallest size are drawn at that size
+	if l := resize(m, 5, 2).layout(); l.width != tinyWidth || l.height != tinyHeight {
+		t.Errorf("Expected the %dx%d layout, got %dx%d", tinyWidth, tinyHeight, l.width, l.height)
+	}
+}
+
+func TestSidebarToggle(t *testing.T) {
+	// The compact layout swaps the main pane for the sidebar
+	m := sendKeys(resize(mouseModel(t), 60, 30), "b")
+	if l := m.layout(); l.leftWidth != 0 || l.rightWidth == 0 {
+		t.Errorf("Expected only the sidebar, got main %d sidebar %d", l.leftWidth, l.rightWidth)
+	}
+
+	// The normal layout hides the sidebar and widens the main pane
+	m = sendKeys(resize(mouseModel(t), 120, 40), "b")
+	if l := m.layout(); l.rightWidth != 0 || l.leftWidth != 120-BoxStyle.GetHorizontalBorderSize() {
+		t.Errorf("Expected the main pane across the window, got main %d sidebar %d", l.leftWidth, l.rightWidth)
+	}
+	checkFits(t, m.View(), 120, 40)
+}
+
+func TestModalsOverlayMainView(t *testing.T) {
+	modals := []struct {
+		keys  []string
+		title string
+	}{
+		{[]string{"n"}, "Start New Entry"},
+		{[]string{"k", "e"}, "Edit Entry"},
+		{[]string{"k", "S"}, "Split Entry"},
+		{[]string{"k", "d"}, "Confirm"},
+		{[]string{"/"}, "Search"},
+		{[]string{"f"}, "Filter by Tag"},
+		{[]string{"?"}, "Help"},
+	}
+	sizes := []struct{ width, height int }{{120, 40}, {60, 20}, {30, 10}}
+	for _, modal := range modals {
+		for _, size := range sizes {
+			m := sendKeys(resize(mouseModel(t), size.width, size.height), modal.keys...)
+			if !m.showModal {
+				t.Fatalf("Expected %v to open the %s modal", modal.keys, modal.title)
+			}
+			view := m.View()
+			checkFits(t, view, size.width, size.height)
+			if size.width < minWidth {
+				continue
+			}
+			text := ansi.Strip(view)
+			if !strings.Contains(text, modal.title) {
+				t.Errorf("Expected the %s modal at %dx%d:\n%s", modal.title, size.width, size.height, text)
+			}
+			// The main view's footer stays visible below the modal
+			if !strings.Contains(text, "[q] Quit") {
+				t.Errorf("Expected the footer below the %s modal at %dx%d:\n%s", modal.title, size.width, size.height, text)
+			}
+		}
+	}
+}
+
+func TestPlaceOverlay(t *testing.T) {
+	background := "abcdef\nghijkl\nmnopqr"
+	got := ansi.Strip(components.PlaceOverlay(2, 1, background, "XY\nZW", 6, 4))
+	want := "abcdef\nghXYkl\nmnZWqr\n"
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	// Overlay centers the foreground and keeps the rows of the screen
+	got = ansi.Strip(components.Overlay(background, "X", 3, 2))
+	if want := "aXcdef\nghijkl"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
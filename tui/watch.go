@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"os"
+	"time"
+
+	"lazytime/storage"
+)
+
+// logStamp identifies a version of the log file by its modification time
+// and size. The TUI polls it on every tick and reloads when it changes, so
+// entries started or stopped from the CLI show up without pressing r.
+type logStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// readLogStamp returns the stamp of the log file; a missing or unreadable
+// file yields the zero stamp.
+func readLogStamp() logStamp {
+	info, err := os.Stat(storage.DefaultLogPath())
+	if err != nil {
+		return logStamp{}
+	}
+	return logStamp{modTime: info.ModTime(), size: info.Size()}
+}
+
+// setEntries replaces the entries with freshly read ones, keeping the
+// selected entry (found again by its start time) and the scroll position.
+func (m *Model) setEntries(entries []storage.Entry) {
+	selectedStart := time.Time{}
+	if m.selected >= 0 && m.selected < len(m.entries) {
+		selectedStart = m.entries[m.selected].Start
+	}
+
+	m.rawEntries = entries
+	m.entries = storage.ApplyTagAliases(entries, m.tagAliases)
+	m.now = storage.LocalNow()
+	m.activeEntryIndex = storage.FindOpen(m.entries)
+
+	if !selectedStart.IsZero() {
+		m.selected = storage.FindByStart(m.entries, selectedStart)
+	}
+	m.clampSelection()
+	if m.showModal && m.modalType == "search" {
+		// Result indices point into the old entries
+		m.searchMatches = searchEntries(m.entries, m.modalInput)
+		m.modalSelected = min(m.modalSelected, max(0, len(m.searchMatches)-1))
+	}
+}
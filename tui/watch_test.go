@@ -0,0 +1,56 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"lazytime/storage"
+)
+
+func TestSetEntriesKeepsSelectionByStart(t *testing.T) {
+	// Entries spanning today stay in the Today list whenever the test runs
+	now := storage.LocalNow()
+	long := func(hoursAgo int, text string) storage.Entry {
+		return closedEntry(now.Add(-time.Duration(hoursAgo)*time.Hour), time.Duration(hoursAgo+24)*time.Hour, text)
+	}
+	m := testModel(t, now, long(72, "A"), long(71, "B"), long(70, "C"))
+	m.selected = 1
+
+	// An entry added before B moves it to index 2
+	m.setEntries([]storage.Entry{long(73, "New"), long(72, "A"), long(71, "B edited"), long(70, "C")})
+	if m.selected != 2 || m.entries[m.selected].Text != "B edited" {
+		t.Errorf("Expected B to stay selected at index 2, got %d", m.selected)
+	}
+
+	// Removing the selected entry clears the selection
+	m.setEntries([]storage.Entry{long(73, "New"), long(72, "A"), long(70, "C")})
+	if m.selected != -1 {
+		t.Errorf("Expected no selection, got %d", m.selected)
+	}
+}
+
+func TestFailedReloadKeepsEntries(t *testing.T) {
+	// A directory in place of the log cannot be read
+	logPath := filepath.Join(t.TempDir(), "log.txt")
+	t.Setenv(storage.LogEnvVar, logPath)
+	if err := os.Mkdir(logPath, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	msg, ok := loadEntriesCmd()().(entriesLoadedMsg)
+	if !ok || msg.err == nil {
+		t.Fatalf("Expected a read error, got %+v", msg)
+	}
+
+	m := searchModel(t)
+	m.selected = 1
+	next, _ := m.Update(msg)
+	m = next.(Model)
+	if len(m.entries) != 3 || len(m.rawEntries) != 3 || m.selected != 1 {
+		t.Errorf("Expected the entries and selection to be kept, got %d entries, selected %d", len(m.entries), m.selected)
+	}
+	if !m.messageError || m.message == "" {
+		t.Errorf("Expected an error message, got %q", m.message)
+	}
+}